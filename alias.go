@@ -0,0 +1,84 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Alias makes Get[To] resolve whatever is registered for From. The alias follows
+// the latest registration of From at resolution time rather than a snapshot, so a
+// lazy singleton is still built lazily and shared between both types. From must be
+// assignable or convertible to To, for example a concrete type and an interface it
+// implements, or two pointer types with the same underlying struct. Aliases that
+// form a cycle fail to resolve with an error naming the cycle. During a migration,
+// register the concrete service once and alias it to both the legacy and the new
+// interface, so each resolves the same instance
+func Alias[From, To any](sl *ServiceLocator) {
+	registerProvider[To](sl, aliasProvider[From, To]{}, false)
+}
+
+// aliasingKey is the context key holding the aliases being resolved
+type aliasingKey struct{}
+
+// aliasing is a stack of alias types being resolved, used to detect cycles
+type aliasing struct {
+	typ    reflect.Type
+	parent *aliasing
+}
+
+// aliasProvider resolves To through the registration of From
+type aliasProvider[From, To any] struct{}
+
+// resolve resolves From and converts the instance to To
+func (aliasProvider[From, To]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	from := getTypeKey[From]().(reflect.Type)
+	to := getTypeKey[To]().(reflect.Type)
+
+	stack, _ := ctx.Value(aliasingKey{}).(*aliasing)
+	for frame := stack; frame != nil; frame = frame.parent {
+		if frame.typ == to {
+			return nil, false, aliasCycleError(stack, to)
+		}
+	}
+	ctx = context.WithValue(ctx, aliasingKey{}, &aliasing{typ: to, parent: stack})
+
+	instance, found, err := sl.resolve(ctx, from)
+	if !found {
+		err = sl.missingError(from)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("alias %v of %v: %w", to, from, err)
+	}
+
+	converted, ok := instance.(To)
+	if !ok {
+		v := reflect.ValueOf(instance)
+		if !v.IsValid() || !v.CanConvert(to) {
+			return nil, false, fmt.Errorf("alias %v of %v: cannot convert %T to %v", to, from, instance, to)
+		}
+		converted = v.Convert(to).Interface().(To)
+	}
+	return decorate(sl, converted), false, nil
+}
+
+// kind reports that an alias is resolved anew on every Get, like a factory
+func (aliasProvider[From, To]) kind() Kind {
+	return KindFactory
+}
+
+// aliasCycleError describes an alias cycle closed by resolving typ again
+func aliasCycleError(stack *aliasing, typ reflect.Type) error {
+	names := []string{typ.String()}
+	for frame := stack; frame != nil; frame = frame.parent {
+		names = append(names, frame.typ.String())
+		if frame.typ == typ {
+			break
+		}
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return fmt.Errorf("alias cycle: %s", strings.Join(names, " -> "))
+}
@@ -0,0 +1,112 @@
+package locator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type DefaultLogger struct {
+	Prefix string
+}
+
+type Logger DefaultLogger
+
+// Test an alias resolves the shared instance of a lazy singleton
+func TestAlias(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *DefaultLogger {
+		callCount++
+		return &DefaultLogger{Prefix: "app"}
+	})
+	locator.Alias[*DefaultLogger, *Logger](sl)
+
+	logger, err := locator.Get[*Logger](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if callCount != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount)
+	}
+
+	defaultLogger, err := locator.Get[*DefaultLogger](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if (*DefaultLogger)(logger) != defaultLogger {
+		t.Fatalf("expected the alias to share the singleton instance")
+	}
+	if callCount != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount)
+	}
+}
+
+// Finder is a newer interface satisfied by the same services as Repository
+type Finder interface {
+	Find(id int) string
+}
+
+// Test one concrete service is reachable under a legacy and a new interface
+func TestAliasMigration(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterLazySingleton(sl, func() *PostgresRepo { return &PostgresRepo{} })
+	locator.Alias[*PostgresRepo, Repository](sl)
+	locator.Alias[*PostgresRepo, Finder](sl)
+
+	legacy, err := locator.Get[Repository](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	finder, err := locator.Get[Finder](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if legacy != finder.(Repository) {
+		t.Fatalf("expected both interfaces to resolve the same instance")
+	}
+}
+
+// Test an alias follows the latest registration of its source
+func TestAliasFollowsRegistration(t *testing.T) {
+	sl := locator.New()
+	locator.Alias[*DefaultLogger, *Logger](sl)
+
+	if _, err := locator.Get[*Logger](sl); err == nil {
+		t.Fatalf("expected error before the source is registered, got nil")
+	}
+
+	locator.RegisterSingleton(sl, &DefaultLogger{Prefix: "first"})
+	locator.RegisterSingleton(sl, &DefaultLogger{Prefix: "second"})
+
+	logger, err := locator.Get[*Logger](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if logger.Prefix != "second" {
+		t.Fatalf("expected second, got %s", logger.Prefix)
+	}
+}
+
+// Test alias cycles and unconvertible aliases are reported
+func TestAliasErrors(t *testing.T) {
+	sl := locator.New()
+	locator.Alias[*ServiceA, *ServiceB](sl)
+	locator.Alias[*ServiceB, *ServiceA](sl)
+
+	_, err := locator.Get[*ServiceA](sl)
+	expected := "alias cycle: *locator_test.ServiceA -> *locator_test.ServiceB -> *locator_test.ServiceA"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected error containing %q, got %v", expected, err)
+	}
+
+	locator.RegisterSingleton(sl, &TestService{})
+	locator.Alias[*TestService, *AnotherTestService](sl)
+	_, err = locator.Get[*AnotherTestService](sl)
+	if err == nil || !strings.Contains(err.Error(), "cannot convert") {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+}
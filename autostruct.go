@@ -0,0 +1,59 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// structConstructor builds a pointer to a struct by resolving its exported fields
+type structConstructor struct {
+	typ reflect.Type
+}
+
+// constructingKey is the context key holding the structs being synthesized
+type constructingKey struct{}
+
+// constructing is a stack of struct types being synthesized, used to reject
+// structs that recursively contain themselves
+type constructing struct {
+	typ    reflect.Type
+	parent *constructing
+}
+
+// resolve allocates the struct and resolves each exported field
+func (sc structConstructor) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	stack, _ := ctx.Value(constructingKey{}).(*constructing)
+	for frame := stack; frame != nil; frame = frame.parent {
+		if frame.typ == sc.typ {
+			return nil, true, fmt.Errorf("cannot construct %v: it recursively contains itself", sc.typ)
+		}
+	}
+	ctx = context.WithValue(ctx, constructingKey{}, &constructing{typ: sc.typ, parent: stack})
+
+	ptr := reflect.New(sc.typ.Elem())
+	v := ptr.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		instance, found, err := sl.resolving(sc.typ).resolve(ctx, field.Type)
+		if !found {
+			err = sl.missingError(field.Type)
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("cannot construct %v: field %s: %w", sc.typ, field.Name, err)
+		}
+		if instance != nil {
+			v.Field(i).Set(reflect.ValueOf(instance))
+		}
+	}
+	return ptr.Interface(), true, nil
+}
+
+// kind reports that synthesized structs behave like factories
+func (sc structConstructor) kind() Kind {
+	return KindFactory
+}
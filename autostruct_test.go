@@ -0,0 +1,77 @@
+package locator_test
+
+import (
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type AutoConstructed struct {
+	Service *TestService
+	Another *AnotherTestService
+	unused  string
+}
+
+type AutoRecursive struct {
+	Next *AutoRecursive
+}
+
+// Test auto-constructing a struct whose fields are registered services
+func TestAutoStructConstruction(t *testing.T) {
+	sl := locator.New(locator.WithAutoStructConstruction())
+
+	service := &TestService{Name: "Service"}
+	locator.RegisterSingleton(sl, service)
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		return &AnotherTestService{ID: 3}
+	})
+
+	auto, err := locator.Get[*AutoConstructed](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if auto.Service != service {
+		t.Fatalf("expected the registered service, got %v", auto.Service)
+	}
+	if auto.Another == nil || auto.Another.ID != 3 {
+		t.Fatalf("expected ID 3, got %v", auto.Another)
+	}
+
+	again, err := locator.Get[*AutoConstructed](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if again == auto {
+		t.Fatalf("expected a new instance on every Get")
+	}
+}
+
+// Test auto-construction errors
+func TestAutoStructConstructionErrors(t *testing.T) {
+	sl := locator.New(locator.WithAutoStructConstruction())
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	_, err := locator.Get[*AutoConstructed](sl)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	// Unregistered struct pointer fields are synthesized too, so the error names
+	// the innermost field that could not be resolved
+	expectedError := "cannot construct *locator_test.AutoConstructed: field Another: " +
+		"cannot construct *locator_test.AnotherTestService: field ID: no provider registered for type int"
+	if err.Error() != expectedError {
+		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+
+	if _, err := locator.Get[*AutoRecursive](sl); err == nil {
+		t.Fatalf("expected error for a recursive struct, got nil")
+	}
+
+	// Without the option unregistered structs are not synthesized
+	plain := locator.New()
+	locator.RegisterSingleton(plain, &TestService{Name: "Service"})
+	locator.RegisterSingleton(plain, &AnotherTestService{ID: 1})
+	if _, err := locator.Get[*AutoConstructed](plain); err == nil {
+		t.Fatalf("expected error without auto construction, got nil")
+	}
+}
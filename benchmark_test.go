@@ -0,0 +1,170 @@
+package locator_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Benchmark Get of a registered singleton
+func BenchmarkGetSingleton(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := locator.Get[*TestService](sl); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark Get of a lazy singleton that is already built
+func BenchmarkGetLazySingleton(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{Name: "Service"} })
+	locator.MustGet[*TestService](sl)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := locator.Get[*TestService](sl); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark Get of a factory
+func BenchmarkGetFactory(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterFactory(sl, func() *TestService { return &TestService{Name: "Service"} })
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := locator.Get[*TestService](sl); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark Get of a singleton from many goroutines
+func BenchmarkGetSingletonParallel(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := locator.Get[*TestService](sl); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// Benchmark Get of a built lazy singleton from many goroutines, counting every
+// resolution of the type
+func BenchmarkGetLazySingletonParallel(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{Name: "Service"} })
+	locator.MustGet[*TestService](sl)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := locator.Get[*TestService](sl); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	if n := sl.Stats().Resolutions["*locator_test.TestService"]; n < uint64(b.N) {
+		b.Fatalf("expected at least %d resolutions, got %d", b.N, n)
+	}
+}
+
+// Benchmark GetNamed of a named singleton
+func BenchmarkGetNamed(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterSingletonNamed(sl, "primary", &TestService{Name: "Service"})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := locator.GetNamed[*TestService](sl, "primary"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark Get of a singleton inherited by a scope
+func BenchmarkGetScope(b *testing.B) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	scope := sl.Scope()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := locator.Get[*TestService](scope); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Test resolving an instantiated singleton does not allocate
+func TestGetZeroAlloc(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService { return &AnotherTestService{} })
+	locator.RegisterSingletonNamed(sl, "primary", &TestService{Name: "Primary"})
+	locator.MustGet[*AnotherTestService](sl)
+	scope := sl.Scope()
+
+	for name, get := range map[string]func(){
+		"Get":            func() { locator.Get[*TestService](sl) },
+		"Get lazy":       func() { locator.Get[*AnotherTestService](sl) },
+		"GetNamed":       func() { locator.GetNamed[*TestService](sl, "primary") },
+		"Get from scope": func() { locator.Get[*TestService](scope) },
+	} {
+		if allocs := testing.AllocsPerRun(100, get); allocs != 0 {
+			t.Fatalf("%s: expected no allocations, got %v", name, allocs)
+		}
+	}
+}
+
+// typeKey mirrors the type key computed by the locator for T
+func typeKey[T any]() any {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// typeKeys caches the keys returned by cachedTypeKey
+var typeKeys sync.Map
+
+// cachedTypeKey looks the type key of T up in a cache keyed by T, the alternative
+// the locator decided against
+func cachedTypeKey[T any]() any {
+	if key, ok := typeKeys.Load((*T)(nil)); ok {
+		return key
+	}
+	key := typeKey[T]()
+	typeKeys.Store((*T)(nil), key)
+	return key
+}
+
+// sinkKey keeps the benchmarked type keys from being optimized away
+var sinkKey any
+
+// Benchmark computing a type key the way the locator does
+func BenchmarkTypeKey(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkKey = typeKey[*TestService]()
+	}
+}
+
+// Benchmark looking a type key up in a sync.Map cache instead
+func BenchmarkTypeKeyCached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkKey = cachedTypeKey[*TestService]()
+	}
+}
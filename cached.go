@@ -0,0 +1,159 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RegisterCachedFactory registers a provider function whose instance is cached and
+// rebuilt only once ttl has elapsed since it was last constructed. Concurrent Get
+// calls during a refresh wait for a single rebuild instead of each running the
+// provider, which runs without holding any lock so it can resolve other types
+func RegisterCachedFactory[T any](sl *ServiceLocator, provider Provider[T], ttl time.Duration) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerCached(sl, nil, provider.withLocator().withError(), ttl)
+}
+
+// RegisterCached registers a provider function whose instance is cached under the
+// current value of keyFn. The instance is rebuilt when keyFn returns a different
+// key, for example after a configuration version bump, or once ttl has elapsed.
+// A provider error is returned from Get and nothing is cached
+func RegisterCached[T any](sl *ServiceLocator, keyFn func() string, provider ProviderE[T], ttl time.Duration) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerCached(sl, keyFn, provider.withLocator(), ttl)
+}
+
+// registerCached stores a cached factory for T
+func registerCached[T any](sl *ServiceLocator, keyFn func() string, build func(*ServiceLocator) (T, error), ttl time.Duration) {
+	registerProvider[T](sl, &cachedFactory[T]{
+		keyFn:    keyFn,
+		provider: build,
+		ttl:      ttl,
+	}, false)
+}
+
+// cachedFactory caches the instance built by provider for ttl, or until the key
+// returned by keyFn changes. Concurrent callers share the rebuild in flight for
+// the same key
+type cachedFactory[T any] struct {
+	mu        sync.RWMutex
+	keyFn     func() string
+	provider  func(*ServiceLocator) (T, error)
+	ttl       time.Duration
+	key       string
+	instance  T
+	expires   time.Time
+	flight    *flight[T]
+	flightKey string
+}
+
+// fresh returns a copy of the cached factory without its cached instance
+func (cf *cachedFactory[T]) fresh() any {
+	return &cachedFactory[T]{keyFn: cf.keyFn, provider: cf.provider, ttl: cf.ttl}
+}
+
+// resolve returns the cached instance, rebuilding it if it has expired
+func (cf *cachedFactory[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	return cf.getInstance(sl)
+}
+
+// kind reports that cf is a cached factory
+func (cf *cachedFactory[T]) kind() Kind {
+	return KindCachedFactory
+}
+
+// getInstance returns the cached instance, rebuilding it if it has expired
+func (cf *cachedFactory[T]) getInstance(sl *ServiceLocator) (T, bool, error) {
+	if cf.provider == nil {
+		var zero T
+		return zero, false, fmt.Errorf("no provider registered for type %v", getTypeKey[T]())
+	}
+
+	var key string
+	if cf.keyFn != nil {
+		key = cf.keyFn()
+	}
+
+	cf.mu.RLock()
+	if cf.valid(key) {
+		instance := cf.instance
+		cf.mu.RUnlock()
+		return instance, false, nil
+	}
+	cf.mu.RUnlock()
+
+	cf.mu.Lock()
+	// Another caller may have rebuilt the instance while we waited for the lock
+	if cf.valid(key) {
+		instance := cf.instance
+		cf.mu.Unlock()
+		return instance, false, nil
+	}
+	// Join the rebuild in flight for key, or start one
+	f, leader := cf.flight, false
+	if f == nil || cf.flightKey != key {
+		f, leader = &flight[T]{done: make(chan struct{})}, true
+		cf.flight, cf.flightKey = f, key
+	}
+	cf.mu.Unlock()
+
+	if leader {
+		cf.build(sl, f, key)
+		return f.instance, true, f.err
+	}
+
+	// Waiting on a rebuild that waits on this goroutine would never return
+	done, cycle := awaitConstruction(getTypeKey[T](), &f.goroutine)
+	if cycle != nil {
+		var zero T
+		return zero, false, cycleError(cycle, sl.callSite)
+	}
+	defer done()
+	<-f.done
+	return f.instance, false, f.err
+}
+
+// build runs the provider for f without holding cf.mu and caches its result under
+// key. Callers waiting on f are released even if the provider panics
+func (cf *cachedFactory[T]) build(sl *ServiceLocator, f *flight[T], key string) {
+	typeKey := getTypeKey[T]()
+	goroutine, leave := enterConstruction(typeKey)
+	f.goroutine.Store(goroutine)
+	completed := false
+	defer func() {
+		leave()
+		if !completed {
+			f.err = fmt.Errorf("provider for type %v panicked", typeKey)
+		}
+		cf.mu.Lock()
+		if cf.flight == f {
+			cf.flight = nil
+		}
+		if f.err == nil {
+			cf.instance = f.instance
+			cf.key = key
+			cf.expires = time.Now().Add(cf.ttl)
+		}
+		cf.mu.Unlock()
+		close(f.done)
+	}()
+
+	instance, err := cf.provider(sl.resolving(typeKey))
+	if err == nil {
+		instance = decorate(sl, instance)
+	}
+	f.instance, f.err = instance, err
+	completed = true
+}
+
+// valid reports whether the cached instance was built for key and has not expired.
+// The caller must hold cf.mu
+func (cf *cachedFactory[T]) valid(key string) bool {
+	return cf.key == key && time.Now().Before(cf.expires)
+}
@@ -0,0 +1,187 @@
+package locator_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test RegisterCachedFactory rebuilds exactly once per TTL window
+func TestCachedFactory(t *testing.T) {
+	sl := locator.New()
+
+	const ttl = 100 * time.Millisecond
+	var callCount int32
+	locator.RegisterCachedFactory(sl, func() *TestService {
+		n := atomic.AddInt32(&callCount, 1)
+		// Keep the provider slow enough for concurrent callers to pile up
+		time.Sleep(10 * time.Millisecond)
+		return &TestService{Name: string(rune('A' + n - 1))}
+	}, ttl)
+
+	resolveConcurrently := func() *TestService {
+		const goroutines = 50
+		results := make(chan *TestService, goroutines)
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for i := 0; i < goroutines; i++ {
+			go func() {
+				defer wg.Done()
+				service, err := locator.Get[*TestService](sl)
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				results <- service
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		first := <-results
+		for service := range results {
+			if service != first {
+				t.Fatalf("expected every caller in a window to share the instance")
+			}
+		}
+		return first
+	}
+
+	first := resolveConcurrently()
+	if n := atomic.LoadInt32(&callCount); n != 1 {
+		t.Fatalf("expected provider to be called once, got %d", n)
+	}
+	if first.Name != "A" {
+		t.Fatalf("expected A, got %v", first.Name)
+	}
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	second := resolveConcurrently()
+	if n := atomic.LoadInt32(&callCount); n != 2 {
+		t.Fatalf("expected provider to be called twice, got %d", n)
+	}
+	if second == first || second.Name != "B" {
+		t.Fatalf("expected a rebuilt instance after the TTL, got %v", second.Name)
+	}
+}
+
+// Test RegisterCached rebuilds when the cache key changes or the TTL expires
+func TestCached(t *testing.T) {
+	sl := locator.New()
+
+	const ttl = 100 * time.Millisecond
+	var mu sync.Mutex
+	key := "v1"
+	var callCount int
+	locator.RegisterCached(sl, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return key
+	}, func() (*TestService, error) {
+		callCount++
+		return &TestService{Name: fmt.Sprintf("Instance%d", callCount)}, nil
+	}, ttl)
+
+	first, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if service != first {
+			t.Fatalf("expected the cached instance for an unchanged key")
+		}
+	}
+	if callCount != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount)
+	}
+
+	mu.Lock()
+	key = "v2"
+	mu.Unlock()
+
+	second, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if second == first || callCount != 2 {
+		t.Fatalf("expected a rebuild after the key changed, got %d calls", callCount)
+	}
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	third, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if third == second || callCount != 3 {
+		t.Fatalf("expected a rebuild after the TTL expired, got %d calls", callCount)
+	}
+}
+
+// Test RegisterCached does not cache provider errors
+func TestCachedError(t *testing.T) {
+	sl := locator.New()
+
+	fail := true
+	locator.RegisterCached(sl, func() string { return "" }, func() (*TestService, error) {
+		if fail {
+			return nil, errors.New("connection refused")
+		}
+		return &TestService{Name: "Recovered"}, nil
+	}, time.Minute)
+
+	_, err := locator.Get[*TestService](sl)
+	if err == nil || err.Error() != "connection refused" {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+
+	fail = false
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Recovered" {
+		t.Fatalf("expected Recovered, got %v", service.Name)
+	}
+}
+
+// Test cached factories reject nil providers and report a provider resolving
+// itself instead of deadlocking
+func TestCachedFactorySafety(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterCachedFactory[*TestService](sl, nil, time.Minute)
+	locator.RegisterCached[*TestService](sl, nil, nil, time.Minute)
+	if err := locator.Validate(sl, (*TestService)(nil)); err == nil {
+		t.Fatalf("expected TestService to stay unregistered")
+	}
+
+	locator.RegisterCachedFactory(sl, func() *AnotherTestService {
+		// Resolving through the captured locator bypasses the resolution frames
+		if _, err := locator.Get[*AnotherTestService](sl); !errors.Is(err, locator.ErrCircularDependency) {
+			t.Errorf("expected %v, got %v", locator.ErrCircularDependency, err)
+		}
+		return &AnotherTestService{}
+	}, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := locator.Get[*AnotherTestService](sl); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the resolution to finish, got a deadlock")
+	}
+}
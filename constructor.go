@@ -0,0 +1,58 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RegisterConstructor registers the type returned by constructor as a lazy
+// singleton built by calling constructor with each of its parameters resolved from
+// the locator by type, so NewUserService(repo *Repo, log *Logger) needs no
+// hand-written provider. constructor must return T or (T, error); a returned
+// error is reported by Get and the construction retried on the next one. The
+// parameter types are declared as dependencies of T, as with DependsOn, except for
+// Optional ones. It panics if constructor does not have such a signature
+func RegisterConstructor(sl *ServiceLocator, constructor any) {
+	v := reflect.ValueOf(constructor)
+	if v.Kind() != reflect.Func {
+		panic(fmt.Errorf("constructor must be a function returning T or (T, error), got %T", constructor))
+	}
+	t := v.Type()
+	if t.IsVariadic() || t.NumOut() == 0 || t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		panic(fmt.Errorf("constructor must be a function returning T or (T, error), got %v", t))
+	}
+	typeKey := t.Out(0)
+	if v.IsNil() {
+		if sl.opts.strictRegistration {
+			panic(fmt.Errorf("nil provider for type %v", typeKey))
+		}
+		return
+	}
+
+	provider := func(sl *ServiceLocator) (any, error) {
+		args, err := sl.resolveArgs(context.Background(), t)
+		if err != nil {
+			return nil, fmt.Errorf("construct %v: %w", typeKey, err)
+		}
+		results := v.Call(args)
+		if len(results) == 2 {
+			if err, _ := results[1].Interface().(error); err != nil {
+				return nil, err
+			}
+		}
+		return results[0].Interface(), nil
+	}
+	sl.registerResolver(typeKey, &lazySingleton[any]{provider: provider, key: typeKey}, false)
+
+	// Optional parameters are resolved if registered, so they are not dependencies
+	var deps []reflect.Type
+	for i := 0; i < t.NumIn(); i++ {
+		if _, ok := optionalElem(t.In(i)); !ok {
+			deps = append(deps, t.In(i))
+		}
+	}
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.dependencies[typeKey] = deps
+}
@@ -0,0 +1,105 @@
+package locator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type UserService struct {
+	Repo    Repository
+	Service *TestService
+}
+
+func NewUserService(repo Repository, service *TestService) *UserService {
+	return &UserService{Repo: repo, Service: service}
+}
+
+// Test RegisterConstructor resolves the constructor's parameters on first Get
+func TestRegisterConstructor(t *testing.T) {
+	sl := locator.New()
+
+	var built int
+	locator.RegisterConstructor(sl, func(repo Repository, service *TestService) *UserService {
+		built++
+		return NewUserService(repo, service)
+	})
+	if built != 0 {
+		t.Fatalf("expected no construction before Get, got %d", built)
+	}
+
+	locator.RegisterAs[Repository](sl, &PostgresRepo{})
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	locator.Decorate(sl, func(u *UserService) *UserService {
+		u.Service = &TestService{Name: "decorated(" + u.Service.Name + ")"}
+		return u
+	})
+
+	users, err := locator.Get[*UserService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if users.Repo.Find(1) != "row 1" || users.Service.Name != "decorated(Service)" {
+		t.Fatalf("expected resolved and decorated dependencies, got %+v", users)
+	}
+	again, _ := locator.Get[*UserService](sl)
+	if again != users || built != 1 {
+		t.Fatalf("expected a singleton built once, got %d constructions", built)
+	}
+}
+
+// Test constructor errors and unresolvable parameters are reported by Get
+func TestRegisterConstructorErrors(t *testing.T) {
+	sl := locator.New()
+
+	errConnect := errors.New("connect failed")
+	locator.RegisterConstructor(sl, func(*TestService) (*AnotherTestService, error) {
+		return nil, errConnect
+	})
+	locator.RegisterConstructor(sl, NewUserService)
+
+	_, err := locator.Get[*UserService](sl)
+	if err == nil || !strings.Contains(err.Error(), "construct *locator_test.UserService: argument 0: no provider registered for type locator_test.Repository") {
+		t.Fatalf("expected missing argument error, got %v", err)
+	}
+
+	locator.RegisterSingleton(sl, &TestService{})
+	if _, err := locator.Get[*AnotherTestService](sl); !errors.Is(err, errConnect) {
+		t.Fatalf("expected %v, got %v", errConnect, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for an invalid constructor")
+		}
+	}()
+	locator.RegisterConstructor(sl, func() (int, string) { return 0, "" })
+}
+
+// Test the constructor's parameters are declared as dependencies of its type
+func TestRegisterConstructorDependencies(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterConstructor(sl, func(repo Repository, service *TestService, config locator.Optional[*Config]) *UserService {
+		return NewUserService(repo, service)
+	})
+	locator.RegisterSingleton(sl, &TestService{})
+
+	err := sl.Validate()
+	if err == nil || !strings.Contains(err.Error(), "*locator_test.UserService depends on unregistered type locator_test.Repository") {
+		t.Fatalf("expected the missing Repository, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Config") {
+		t.Fatalf("expected the optional parameter not to be required, got %v", err)
+	}
+
+	locator.RegisterAs[Repository](sl, &PostgresRepo{})
+	if err := sl.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	deps := sl.DependencyGraph()["*locator_test.UserService"]
+	if len(deps) != 2 || deps[0] != "*locator_test.TestService" || deps[1] != "locator_test.Repository" {
+		t.Fatalf("expected the parameters in the graph, got %v", deps)
+	}
+}
@@ -0,0 +1,19 @@
+package locator
+
+import "context"
+
+// locatorKey is the context key holding a ServiceLocator
+type locatorKey struct{}
+
+// ContextWithLocator returns a copy of ctx carrying sl, typically a request scoped
+// locator that handlers retrieve with FromContext
+func ContextWithLocator(ctx context.Context, sl *ServiceLocator) context.Context {
+	return context.WithValue(ctx, locatorKey{}, sl)
+}
+
+// FromContext returns the locator stored in ctx by ContextWithLocator. It returns
+// nil and false when ctx carries no locator
+func FromContext(ctx context.Context) (*ServiceLocator, bool) {
+	sl, ok := ctx.Value(locatorKey{}).(*ServiceLocator)
+	return sl, ok && sl != nil
+}
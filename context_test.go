@@ -0,0 +1,40 @@
+package locator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test a locator round trips through a context
+func TestContextWithLocator(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "FromContext"})
+
+	ctx := locator.ContextWithLocator(context.Background(), sl)
+	fromCtx, ok := locator.FromContext(ctx)
+	if !ok || fromCtx != sl {
+		t.Fatalf("expected the stored locator, got %p (%v)", fromCtx, ok)
+	}
+
+	service, err := locator.Get[*TestService](fromCtx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "FromContext" {
+		t.Fatalf("expected FromContext, got %s", service.Name)
+	}
+}
+
+// Test FromContext reports a missing locator
+func TestFromContextMissing(t *testing.T) {
+	if sl, ok := locator.FromContext(context.Background()); ok || sl != nil {
+		t.Fatalf("expected nil and false, got %p and %v", sl, ok)
+	}
+
+	ctx := locator.ContextWithLocator(context.Background(), nil)
+	if sl, ok := locator.FromContext(ctx); ok || sl != nil {
+		t.Fatalf("expected nil and false for a nil locator, got %p and %v", sl, ok)
+	}
+}
@@ -0,0 +1,85 @@
+package locator
+
+import "reflect"
+
+// Decorate installs a transform applied to every instance of T the locator hands
+// out. Decorators run inside Get after the base instance is produced and compose in
+// registration order. For singletons, lazy singletons and cached factories the
+// decorated value is what gets cached, so subsequent Gets return the same wrapped
+// instance. A singleton that is already materialized is wrapped immediately
+func Decorate[T any](sl *ServiceLocator, decorator func(T) T) {
+	typeKey := getTypeKey[T]()
+
+	sl.mu.Lock()
+	if err := sl.frozenError(typeKey); err != nil {
+		sl.mu.Unlock()
+		panic(err)
+	}
+	decorators, _ := sl.decorators[typeKey].([]func(T) T)
+	// Copy on append so a concurrent decorate never sees a partially updated slice
+	sl.decorators[typeKey] = append(decorators[:len(decorators):len(decorators)], decorator)
+	if cache, ok := sl.providers[typeKey].(decoratedCache); ok {
+		sl.providers[typeKey] = cache.withoutDecorated()
+	}
+	sl.changed()
+	instance, exists := sl.instances[typeKey]
+	sl.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	// The decorator runs without holding the lock so it can resolve from the locator
+	decorated := decorator(castInstance[T](instance))
+	sl.mu.Lock()
+	if _, exists := sl.instances[typeKey]; exists {
+		sl.instances[typeKey] = decorated
+		sl.changed()
+	}
+	sl.mu.Unlock()
+}
+
+// decoratedCache is implemented by providers that cache the decorated form of
+// instances registered up front. withoutDecorated returns a copy with an empty
+// cache, which Decorate installs so that the new decorator applies
+type decoratedCache interface {
+	withoutDecorated() any
+}
+
+// decorate applies the decorators registered for T to instance in registration
+// order. In a scope the decorators of its ancestors run first
+func decorate[T any](sl *ServiceLocator, instance T) T {
+	return decorateAs(sl, getTypeKey[T](), instance)
+}
+
+// decorateAs applies the decorators registered for typeKey to instance. T is
+// usually the type of typeKey; when it is not, as for instances built by
+// RegisterConstructor, the decorators are called through reflection
+func decorateAs[T any](sl *ServiceLocator, typeKey any, instance T) T {
+	if sl.parent != nil {
+		instance = decorateAs(sl.parent, typeKey, instance)
+	}
+
+	decorators := sl.view().decorators[typeKey]
+
+	if typed, ok := decorators.([]func(T) T); ok {
+		for _, decorator := range typed {
+			instance = decorator(instance)
+		}
+		return instance
+	}
+	if decorators == nil {
+		return instance
+	}
+
+	v := reflect.ValueOf(decorators)
+	for i := 0; i < v.Len(); i++ {
+		decorator := v.Index(i)
+		arg := reflect.ValueOf(any(instance))
+		if !arg.IsValid() {
+			arg = reflect.Zero(decorator.Type().In(0))
+		}
+		instance, _ = decorator.Call([]reflect.Value{arg})[0].Interface().(T)
+	}
+	return instance
+}
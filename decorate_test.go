@@ -0,0 +1,156 @@
+package locator_test
+
+import (
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type baseGreeter struct{}
+
+func (baseGreeter) Greet() string {
+	return "hello"
+}
+
+type wrappedGreeter struct {
+	inner Greeter
+	tag   string
+}
+
+func (g *wrappedGreeter) Greet() string {
+	return g.tag + "(" + g.inner.Greet() + ")"
+}
+
+func wrapWith(tag string) func(*TestService) *TestService {
+	return func(s *TestService) *TestService {
+		return &TestService{Name: tag + "(" + s.Name + ")"}
+	}
+}
+
+// Test decorators compose in registration order for factories
+func TestDecorateFactory(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterFactory(sl, func() *TestService {
+		return &TestService{Name: "base"}
+	})
+	locator.Decorate(sl, wrapWith("tracing"))
+	locator.Decorate(sl, wrapWith("retry"))
+
+	for i := 0; i < 2; i++ {
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if service.Name != "retry(tracing(base))" {
+			t.Fatalf("expected retry(tracing(base)), got %v", service.Name)
+		}
+	}
+}
+
+// Test the decorated value is what a lazy singleton caches
+func TestDecorateLazySingleton(t *testing.T) {
+	sl := locator.New()
+
+	var decorateCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		return &TestService{Name: "base"}
+	})
+	locator.Decorate(sl, func(s *TestService) *TestService {
+		decorateCount++
+		return &TestService{Name: "traced(" + s.Name + ")"}
+	})
+
+	first, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected the same decorated instance")
+	}
+	if first.Name != "traced(base)" {
+		t.Fatalf("expected traced(base), got %v", first.Name)
+	}
+	if decorateCount != 1 {
+		t.Fatalf("expected decorator to run once, got %d", decorateCount)
+	}
+}
+
+// Test decorating singletons registered before and after the decorator
+func TestDecorateSingleton(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton[Greeter](sl, baseGreeter{})
+	locator.Decorate(sl, func(g Greeter) Greeter {
+		return &wrappedGreeter{inner: g, tag: "log"}
+	})
+
+	greeter, err := locator.Get[Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "log(hello)" {
+		t.Fatalf("expected log(hello), got %v", greeter.Greet())
+	}
+
+	locator.RegisterSingleton[Greeter](sl, baseGreeter{})
+	greeter, err = locator.Get[Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "log(hello)" {
+		t.Fatalf("expected log(hello) after re-registering, got %v", greeter.Greet())
+	}
+}
+
+// Test decorators stack over a service bound to an interface
+func TestDecorateBoundInterface(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterAs[Greeter](sl, baseGreeter{})
+	locator.Decorate(sl, func(g Greeter) Greeter {
+		return &wrappedGreeter{inner: g, tag: "log"}
+	})
+	locator.Decorate(sl, func(g Greeter) Greeter {
+		return &wrappedGreeter{inner: g, tag: "retry"}
+	})
+
+	greeter, err := locator.Get[Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "retry(log(hello))" {
+		t.Fatalf("expected retry(log(hello)), got %v", greeter.Greet())
+	}
+}
+
+// Test a singleton registered as a nil interface is decorated with the nil value
+func TestDecorateNilInterface(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton[Greeter](sl, nil)
+
+	var received Greeter = baseGreeter{}
+	locator.Decorate(sl, func(g Greeter) Greeter {
+		received = g
+		return &wrappedGreeter{inner: baseGreeter{}, tag: "default"}
+	})
+	if received != nil {
+		t.Fatalf("expected the decorator to receive nil, got %v", received)
+	}
+
+	greeter, err := locator.Get[Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "default(hello)" {
+		t.Fatalf("expected default(hello), got %v", greeter.Greet())
+	}
+}
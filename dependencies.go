@@ -0,0 +1,334 @@
+package locator
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DependsOn declares the types the provider of T resolves when it runs. Declared
+// dependencies are used to plan resolutions; they do not change how T is built.
+// Declaring again replaces the previous list
+func DependsOn[T any](sl *ServiceLocator, deps ...reflect.Type) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(getTypeKey[T]())
+	sl.dependencies[getTypeKey[T]()] = append([]reflect.Type(nil), deps...)
+}
+
+// ResolutionPlan returns the transitive closure of the declared dependencies of t,
+// ordered so that every type comes after the types it depends on and t comes last.
+// It fails if a type in the closure is not registered or the dependencies form a cycle
+func ResolutionPlan(sl *ServiceLocator, t reflect.Type) ([]reflect.Type, error) {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	closure, err := sl.dependencyOrder(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kahn's algorithm from the root: a type is emitted once every type depending
+	// on it has been emitted. Reversing that order puts dependencies first
+	dependents := make(map[reflect.Type]int, len(closure))
+	for _, typ := range closure {
+		for _, dep := range sl.dependencies[typ] {
+			dependents[dep]++
+		}
+	}
+
+	order := make([]reflect.Type, 0, len(closure))
+	queue := []reflect.Type{t}
+	for len(queue) > 0 {
+		typ := queue[0]
+		queue = queue[1:]
+		order = append(order, typ)
+		for _, dep := range sl.dependencies[typ] {
+			dependents[dep]--
+			if dependents[dep] == 0 {
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order, nil
+}
+
+// dependencyOrder returns every type reachable from roots through declared
+// dependencies, including the roots themselves, with each type placed after its
+// dependencies. The caller must hold sl.mu
+func (sl *ServiceLocator) dependencyOrder(roots ...reflect.Type) ([]reflect.Type, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[reflect.Type]int)
+	var order []reflect.Type
+	var path []reflect.Type
+
+	var visit func(typ reflect.Type) error
+	visit = func(typ reflect.Type) error {
+		switch state[typ] {
+		case visited:
+			return nil
+		case visiting:
+			return cycleError(append(path, typ), func(typ reflect.Type) string { return sl.sites[typ] })
+		}
+		if !sl.isRegistered(typ) {
+			if len(path) == 0 {
+				return notRegistered(typ)
+			}
+			return fmt.Errorf("%v depends on unregistered type %v", path[len(path)-1], typ)
+		}
+
+		state[typ] = visiting
+		path = append(path, typ)
+		for _, dep := range sl.dependencies[typ] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[typ] = visited
+		order = append(order, typ)
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := visit(root); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// cycleError describes a dependency cycle wrapping ErrCircularDependency. path
+// ends with the type that closes it. Each type in the cycle is followed by its
+// registration call site as returned by site, so the message shows where to
+// break the cycle
+func cycleError[K comparable](path []K, site func(K) string) error {
+	start := 0
+	for i, typ := range path {
+		if typ == path[len(path)-1] {
+			start = i
+			break
+		}
+	}
+
+	names := make([]string, 0, len(path)-start)
+	for i, typ := range path[start:] {
+		name := fmt.Sprint(typ)
+		if s := site(typ); s != "" && i < len(path)-start-1 {
+			name += " (" + s + ")"
+		}
+		names = append(names, name)
+	}
+	return fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(names, " -> "))
+}
+
+// DependencyGraph maps the name of each registered type to the sorted names of
+// the types its provider depends on. Dependencies come from DependsOn declarations
+// and from the resolutions locator-aware providers have made so far, so resolving
+// services first, for example with Warmup, makes the graph complete
+func (sl *ServiceLocator) DependencyGraph() map[string][]string {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	deps := make(map[any]map[any]struct{})
+	addNode := func(typeKey any) {
+		if deps[typeKey] == nil {
+			deps[typeKey] = make(map[any]struct{})
+		}
+	}
+	for typeKey := range sl.instances {
+		addNode(typeKey)
+	}
+	for typeKey := range sl.providers {
+		addNode(typeKey)
+	}
+	for typeKey, declared := range sl.dependencies {
+		if node, exists := deps[typeKey]; exists {
+			for _, dep := range declared {
+				node[dep] = struct{}{}
+			}
+		}
+	}
+	for typeKey, recorded := range sl.edges {
+		if node, exists := deps[typeKey]; exists {
+			for dep := range recorded {
+				node[dep] = struct{}{}
+			}
+		}
+	}
+
+	graph := make(map[string][]string, len(deps))
+	for typeKey, node := range deps {
+		names := make([]string, 0, len(node))
+		for dep := range node {
+			names = append(names, fmt.Sprint(dep))
+		}
+		sort.Strings(names)
+		graph[fmt.Sprint(typeKey)] = names
+	}
+	return graph
+}
+
+// resolutionFrame is a link in the chain of types being built by nested providers
+type resolutionFrame struct {
+	typeKey any
+	parent  *resolutionFrame
+}
+
+// cycle returns the path from the outermost type being built to typeKey if typeKey
+// is already being built further up the chain, or nil otherwise
+func (f *resolutionFrame) cycle(typeKey any) []any {
+	var path []any
+	inChain := false
+	for frame := f; frame != nil; frame = frame.parent {
+		path = append(path, frame.typeKey)
+		inChain = inChain || frame.typeKey == typeKey
+	}
+	if !inChain {
+		return nil
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return append(path, typeKey)
+}
+
+// building maps the id of each goroutine running lazy providers to the keys
+// it is building, outermost first. It catches cycles that resolving cannot, as
+// when a provider resolves through a locator it captured instead of the one it
+// was handed
+var building sync.Map // goroutine id -> []any
+
+// goroutineID returns the id of the calling goroutine, parsed from the header of
+// its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+// enterConstruction records that the calling goroutine builds typeKey. It returns
+// the goroutine's id and a function to call once the construction is over
+func enterConstruction(typeKey any) (goroutine uint64, leave func()) {
+	goroutine = goroutineID()
+	outer, _ := building.Load(goroutine)
+	keys, _ := outer.([]any)
+	building.Store(goroutine, append(keys[:len(keys):len(keys)], typeKey))
+	return goroutine, func() {
+		if len(keys) == 0 {
+			building.Delete(goroutine)
+		} else {
+			building.Store(goroutine, keys)
+		}
+	}
+}
+
+// goroutineStack holds a stack of values per goroutine, for checks that follow the
+// resolutions providers make while they run
+type goroutineStack[T any] struct {
+	m sync.Map // goroutine id -> []T
+}
+
+// push puts v on top of the stack of the calling goroutine. It returns a function
+// removing it, to call once the work v describes is over
+func (s *goroutineStack[T]) push(v T) (pop func()) {
+	goroutine := goroutineID()
+	outer, _ := s.m.Load(goroutine)
+	values, _ := outer.([]T)
+	s.m.Store(goroutine, append(values[:len(values):len(values)], v))
+	return func() {
+		if len(values) == 0 {
+			s.m.Delete(goroutine)
+		} else {
+			s.m.Store(goroutine, values)
+		}
+	}
+}
+
+// top returns the value on top of the stack of the calling goroutine
+func (s *goroutineStack[T]) top() (T, bool) {
+	outer, _ := s.m.Load(goroutineID())
+	values, _ := outer.([]T)
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return values[len(values)-1], true
+}
+
+// waiting maps the id of each goroutine blocked on a lazy construction to the id
+// of the goroutine running it, which is 0 until that goroutine starts
+var waiting sync.Map // goroutine id -> *atomic.Uint64
+
+// awaitConstruction records that the calling goroutine is about to wait for the
+// construction of typeKey run by the goroutine whose id owner holds, and returns
+// a function to call once the wait is over. If that construction waits, directly
+// or through others, on the calling goroutine it returns the path of the cycle
+// instead. Both sides of a cycle register before checking, so at least one of
+// them sees it
+func awaitConstruction(typeKey any, owner *atomic.Uint64) (done func(), cycle []any) {
+	self := goroutineID()
+	waiting.Store(self, owner)
+
+	var path []any
+	seen := make(map[uint64]bool)
+	for g := owner.Load(); g != 0 && !seen[g]; {
+		seen[g] = true
+		keys, _ := building.Load(g)
+		outer, _ := keys.([]any)
+		path = append(path, outer...)
+		if g == self {
+			waiting.Delete(self)
+			return nil, append(path, typeKey)
+		}
+		next, ok := waiting.Load(g)
+		if !ok {
+			break
+		}
+		g = next.(*atomic.Uint64).Load()
+	}
+	return func() { waiting.Delete(self) }, nil
+}
+
+// resolving returns a view of the locator for the provider of typeKey. Resolutions
+// made through the view are recorded as dependencies of typeKey, and resolving a
+// type that is still being built up the chain fails with a cycle error rather than
+// waiting on its own construction
+func (sl *ServiceLocator) resolving(typeKey any) *ServiceLocator {
+	return &ServiceLocator{registry: sl.registry, frame: &resolutionFrame{typeKey: typeKey, parent: sl.frame}, trace: sl.trace}
+}
+
+// recordEdge records that the provider of typeKey resolved dep
+func (sl *ServiceLocator) recordEdge(typeKey, dep any) {
+	sl.mu.RLock()
+	_, exists := sl.edges[typeKey][dep]
+	sl.mu.RUnlock()
+	if exists {
+		return
+	}
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if sl.edges[typeKey] == nil {
+		sl.edges[typeKey] = make(map[any]struct{})
+	}
+	sl.edges[typeKey][dep] = struct{}{}
+}
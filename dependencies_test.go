@@ -0,0 +1,199 @@
+package locator_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type ServiceA struct{}
+type ServiceB struct{}
+type ServiceC struct{}
+type ServiceD struct{}
+
+var (
+	typeA = reflect.TypeOf(&ServiceA{})
+	typeB = reflect.TypeOf(&ServiceB{})
+	typeC = reflect.TypeOf(&ServiceC{})
+	typeD = reflect.TypeOf(&ServiceD{})
+)
+
+// registerABCD registers A depending on B and C, and C depending on D
+func registerABCD(sl *locator.ServiceLocator) {
+	locator.RegisterLazySingleton(sl, func() *ServiceA { return &ServiceA{} })
+	locator.RegisterLazySingleton(sl, func() *ServiceB { return &ServiceB{} })
+	locator.RegisterLazySingleton(sl, func() *ServiceC { return &ServiceC{} })
+	locator.RegisterLazySingleton(sl, func() *ServiceD { return &ServiceD{} })
+	locator.DependsOn[*ServiceA](sl, typeB, typeC)
+	locator.DependsOn[*ServiceC](sl, typeD)
+}
+
+// Test ResolutionPlan orders dependencies before dependents
+func TestResolutionPlan(t *testing.T) {
+	sl := locator.New()
+	registerABCD(sl)
+
+	plan, err := locator.ResolutionPlan(sl, typeA)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []reflect.Type{typeD, typeC, typeB, typeA}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("expected %v, got %v", expected, plan)
+	}
+
+	plan, err = locator.ResolutionPlan(sl, typeC)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected = []reflect.Type{typeD, typeC}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("expected %v, got %v", expected, plan)
+	}
+}
+
+// Test ResolutionPlan reports missing dependencies and cycles
+func TestResolutionPlanErrors(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterLazySingleton(sl, func() *ServiceA { return &ServiceA{} })
+	locator.DependsOn[*ServiceA](sl, typeB)
+
+	_, err := locator.ResolutionPlan(sl, typeA)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectedError := "*locator_test.ServiceA depends on unregistered type *locator_test.ServiceB"
+	if err.Error() != expectedError {
+		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+
+	locator.RegisterLazySingleton(sl, func() *ServiceB { return &ServiceB{} })
+	locator.DependsOn[*ServiceB](sl, typeA)
+
+	_, err = locator.ResolutionPlan(sl, typeA)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectCycle(t, err, "dependencies_test.go", "*locator_test.ServiceA", "*locator_test.ServiceB", "*locator_test.ServiceA")
+}
+
+// expectCycle fails the test unless err wraps ErrCircularDependency and names the
+// cycle through names, each but the last followed by its call site in file
+func expectCycle(t *testing.T, err error, file string, names ...string) {
+	t.Helper()
+	if !errors.Is(err, locator.ErrCircularDependency) {
+		t.Fatalf("expected a circular dependency error, got %v", err)
+	}
+	pattern := "circular dependency: "
+	for i, name := range names {
+		if i > 0 {
+			pattern += " -> "
+		}
+		pattern += regexp.QuoteMeta(name)
+		if i < len(names)-1 {
+			pattern += ` \(\S*` + regexp.QuoteMeta(file) + `:\d+\)`
+		}
+	}
+	if !regexp.MustCompile(pattern).MatchString(err.Error()) {
+		t.Fatalf("expected error matching %q, got %v", pattern, err)
+	}
+}
+
+// Test DependencyGraph combines declared and recorded dependencies
+func TestDependencyGraph(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &ServiceD{})
+	locator.RegisterSingleton(sl, &ServiceB{})
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceC {
+		locator.Get[*ServiceD](sl)
+		return &ServiceC{}
+	})
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceA {
+		locator.Get[*ServiceC](sl)
+		return &ServiceA{}
+	})
+	locator.DependsOn[*ServiceA](sl, typeB)
+
+	if _, err := locator.Get[*ServiceA](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[string][]string{
+		typeA.String(): {typeB.String(), typeC.String()},
+		typeB.String(): {},
+		typeC.String(): {typeD.String()},
+		typeD.String(): {},
+	}
+	if graph := sl.DependencyGraph(); !reflect.DeepEqual(graph, expected) {
+		t.Fatalf("expected %v, got %v", expected, graph)
+	}
+}
+
+// Test resolving a type that is still being built fails with the cycle path
+func TestResolveCycle(t *testing.T) {
+	sl := locator.New()
+	var cycleErr error
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceA {
+		locator.Get[*ServiceB](sl)
+		return &ServiceA{}
+	})
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceB {
+		_, cycleErr = locator.Get[*ServiceA](sl)
+		return &ServiceB{}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		locator.Get[*ServiceA](sl)
+		done <- cycleErr
+	}()
+
+	select {
+	case err := <-done:
+		expectCycle(t, err, "dependencies_test.go", typeA.String(), typeB.String(), typeA.String())
+	case <-time.After(time.Second):
+		t.Fatalf("expected cycle error, got deadlock")
+	}
+}
+
+type CycleA struct{ B *CycleB }
+type CycleB struct{ A *CycleA }
+
+// Test providers resolving through a captured locator fail on a cycle instead of
+// waiting on their own construction
+func TestResolveCycleCapturedLocator(t *testing.T) {
+	for _, opts := range [][]locator.Option{nil, {locator.WithConstructTimeout(5 * time.Second)}} {
+		sl := locator.New(opts...)
+		locator.RegisterLazySingletonE(sl, func() (*CycleA, error) {
+			b, err := locator.Get[*CycleB](sl)
+			return &CycleA{B: b}, err
+		})
+		locator.RegisterLazySingletonE(sl, func() (*CycleB, error) {
+			a, err := locator.Get[*CycleA](sl)
+			return &CycleB{A: a}, err
+		})
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := locator.Get[*CycleA](sl)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			expectCycle(t, err, "dependencies_test.go", "*locator_test.CycleA", "*locator_test.CycleB", "*locator_test.CycleA")
+		case <-time.After(time.Second):
+			t.Fatalf("expected a cycle error, got a deadlock")
+		}
+
+		// The failed constructions are retried rather than left in flight
+		locator.RegisterSingleton(sl, &CycleB{})
+		if _, err := locator.Get[*CycleA](sl); err != nil {
+			t.Fatalf("expected no error once the cycle is broken, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package locator
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Dump writes a table of every registration to w, one row per type with its
+// lifetime, whether it is instantiated, where it was registered and how long its
+// construction took when known, for example to attach to a bug report. Nothing is
+// constructed
+func (sl *ServiceLocator) Dump(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tLIFETIME\tINSTANTIATED\tREGISTERED AT\tBUILD TIME")
+	for _, info := range sl.Registrations() {
+		instantiated, site, duration := "no", "-", "-"
+		if info.Instantiated {
+			instantiated = "yes"
+		}
+		if info.CallSite != "" {
+			site = info.CallSite
+		}
+		if info.Duration > 0 {
+			duration = info.Duration.String()
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%s\t%s\n", info.Type, info.Kind, instantiated, site, duration)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,42 @@
+package locator_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Dump writes one row per registration
+func TestDump(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		time.Sleep(time.Millisecond)
+		return &AnotherTestService{}
+	})
+	locator.RegisterFactory(sl, func() int { return 42 })
+	locator.MustGet[*AnotherTestService](sl)
+
+	var out strings.Builder
+	if err := sl.Dump(&out); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got %q", out.String())
+	}
+	for i, pattern := range []string{
+		`^TYPE +LIFETIME +INSTANTIATED +REGISTERED AT +BUILD TIME$`,
+		`^\*locator_test\.AnotherTestService +lazy +yes +\S*dump_test\.go:\d+ +\d[\d.]*[µm]?s$`,
+		`^\*locator_test\.TestService +singleton +yes +\S*dump_test\.go:\d+ +-$`,
+		`^int +factory +no +\S*dump_test\.go:\d+ +-$`,
+	} {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("expected line %d to match %q, got %q", i, pattern, lines[i])
+		}
+	}
+}
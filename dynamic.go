@@ -0,0 +1,67 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RegisterDynamic registers provider as the lazy singleton of typ, for frameworks
+// that discover types at runtime, such as codecs or message handlers, and cannot
+// use type parameters. The registration is the same as one made for typ with
+// RegisterLazySingletonE, so it can also be resolved with Get. Get fails if
+// provider returns a value that is not assignable to typ
+func RegisterDynamic(sl *ServiceLocator, typ reflect.Type, provider func() (any, error)) {
+	if provider == nil {
+		if sl.opts.strictRegistration {
+			panic(fmt.Errorf("nil provider for type %v", typ))
+		}
+		return
+	}
+	wrapped := func(*ServiceLocator) (any, error) {
+		instance, err := provider()
+		if err != nil {
+			return nil, err
+		}
+		if !assignable(instance, typ) {
+			return nil, fmt.Errorf("provider for type %v returned %s", typ, describe(instance))
+		}
+		return instance, nil
+	}
+	sl.registerResolver(typ, &lazySingleton[any]{provider: wrapped, key: typ}, false)
+}
+
+// GetDynamic retrieves the instance registered for typ, however it was
+// registered. It is the counterpart of Get for types only known at runtime
+func GetDynamic(sl *ServiceLocator, typ reflect.Type) (any, error) {
+	instance, found, err := sl.resolve(context.Background(), typ)
+	if !found {
+		return nil, sl.missingError(typ)
+	}
+	return instance, err
+}
+
+// assignable reports whether instance can be returned for typ, nil included
+func assignable(instance any, typ reflect.Type) bool {
+	if instance == nil {
+		return canBeNil(typ)
+	}
+	return reflect.TypeOf(instance).AssignableTo(typ)
+}
+
+// describe names the dynamic type of instance for error messages
+func describe(instance any) string {
+	if instance == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+// canBeNil reports whether nil is a valid value of typ
+func canBeNil(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package locator_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test types registered by reflect.Type resolve through GetDynamic and Get
+func TestDynamic(t *testing.T) {
+	sl := locator.New()
+
+	var builds int
+	serviceType := reflect.TypeOf((*TestService)(nil))
+	locator.RegisterDynamic(sl, serviceType, func() (any, error) {
+		builds++
+		return &TestService{Name: "dynamic"}, nil
+	})
+	locator.RegisterDynamic(sl, reflect.TypeOf((*Greeter)(nil)).Elem(), func() (any, error) {
+		return baseGreeter{}, nil
+	})
+
+	instance, err := locator.GetDynamic(sl, serviceType)
+	if s, ok := instance.(*TestService); err != nil || !ok || s.Name != "dynamic" {
+		t.Fatalf("expected the dynamic service, got %v, %v", instance, err)
+	}
+	if service, err := locator.Get[*TestService](sl); err != nil || service != instance || builds != 1 {
+		t.Fatalf("expected Get to return the same singleton, got %v, %v after %d builds", service, err, builds)
+	}
+	if greeter, err := locator.Get[Greeter](sl); err != nil || greeter.Greet() != "hello" {
+		t.Fatalf("expected the greeter, got %v, %v", greeter, err)
+	}
+
+	locator.RegisterSingleton(sl, 42)
+	if n, err := locator.GetDynamic(sl, reflect.TypeOf(0)); err != nil || n != 42 {
+		t.Fatalf("expected a generic registration to resolve, got %v, %v", n, err)
+	}
+}
+
+// Test GetDynamic reports missing types, failing providers and mistyped values
+func TestDynamicErrors(t *testing.T) {
+	sl := locator.New()
+
+	anotherType := reflect.TypeOf((*AnotherTestService)(nil))
+	if _, err := locator.GetDynamic(sl, anotherType); err == nil || err.Error() != "no provider registered for type *locator_test.AnotherTestService" {
+		t.Fatalf("expected missing error, got %v", err)
+	}
+
+	errDecode := errors.New("decode failed")
+	locator.RegisterDynamic(sl, anotherType, func() (any, error) { return nil, errDecode })
+	if _, err := locator.GetDynamic(sl, anotherType); !errors.Is(err, errDecode) {
+		t.Fatalf("expected %v, got %v", errDecode, err)
+	}
+
+	locator.RegisterDynamic(sl, reflect.TypeOf(""), func() (any, error) { return 42, nil })
+	if _, err := locator.GetDynamic(sl, reflect.TypeOf("")); err == nil || !strings.Contains(err.Error(), "provider for type string returned int") {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+}
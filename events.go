@@ -0,0 +1,121 @@
+package locator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventOp is the change to the locator an Event reports
+type EventOp int
+
+const (
+	// EventRegister is reported when a type without a registration is registered
+	EventRegister EventOp = iota
+	// EventOverwrite is reported when a registration replaces an existing one
+	EventOverwrite
+	// EventUnregister is reported when a registration is removed
+	EventUnregister
+	// EventInstantiate is reported when a lazy singleton or scoped type is built
+	EventInstantiate
+)
+
+// String returns the name of the operation
+func (op EventOp) String() string {
+	switch op {
+	case EventRegister:
+		return "register"
+	case EventOverwrite:
+		return "overwrite"
+	case EventUnregister:
+		return "unregister"
+	case EventInstantiate:
+		return "instantiate"
+	default:
+		return fmt.Sprintf("EventOp(%d)", int(op))
+	}
+}
+
+// Event describes a change to the registrations of a locator reported to its
+// subscribers
+type Event struct {
+	// Op is the kind of change
+	Op EventOp
+	// Type names the registration as Registrations does
+	Type string
+	// Kind is the lifetime of the registration, KindUnregistered for EventUnregister
+	Kind Kind
+	// CallSite is the file:line of the registering call, empty if unknown or for
+	// other operations
+	CallSite string
+	// Duration is the time the provider took for EventInstantiate, zero otherwise
+	Duration time.Duration
+}
+
+// Subscribe installs fn to be called with every registration, overwrite and
+// unregistration on the locator and the first instantiation of each lazy
+// singleton and scoped type, for example to mirror the locator in a dashboard.
+// Events are delivered in order from a separate goroutine, one at a time, so fn may
+// use the locator but sees the events shortly after they happen. Scopes have
+// subscribers of their own
+func (sl *ServiceLocator) Subscribe(fn func(ev Event)) {
+	q := &sl.events
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// Copy on append so events already queued keep the subscribers they were sent to
+	q.subscribers = append(q.subscribers[:len(q.subscribers):len(q.subscribers)], fn)
+}
+
+// eventQueue holds the events waiting to be delivered to subscribers
+type eventQueue struct {
+	mu          sync.Mutex
+	subscribers []func(Event)
+	pending     []queuedEvent
+	draining    bool
+}
+
+// queuedEvent is an event together with the subscribers it is sent to
+type queuedEvent struct {
+	ev          Event
+	subscribers []func(Event)
+}
+
+// emit queues an event about typeKey for the subscribers of sl. It may be called
+// with or without sl.mu held
+func (sl *ServiceLocator) emit(op EventOp, typeKey any, kind Kind, site string, d time.Duration) {
+	q := &sl.events
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.subscribers) == 0 {
+		return
+	}
+
+	ev := Event{Op: op, Type: fmt.Sprint(typeKey), Kind: kind, CallSite: site, Duration: d}
+	q.pending = append(q.pending, queuedEvent{ev: ev, subscribers: q.subscribers})
+	if !q.draining {
+		q.draining = true
+		go q.drain()
+	}
+}
+
+// drain delivers queued events until none is left. Only one drain runs at a time,
+// which keeps the events in order
+func (q *eventQueue) drain() {
+	for {
+		q.mu.Lock()
+		batch := q.pending
+		q.pending = nil
+		if len(batch) == 0 {
+			q.draining = false
+			q.mu.Unlock()
+			return
+		}
+		q.mu.Unlock()
+
+		for _, queued := range batch {
+			for _, fn := range queued.subscribers {
+				fn(queued.ev)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package locator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// subscribe returns a channel receiving the events of sl
+func subscribe(sl *locator.ServiceLocator) <-chan locator.Event {
+	events := make(chan locator.Event, 100)
+	sl.Subscribe(func(ev locator.Event) { events <- ev })
+	return events
+}
+
+// nextEvent waits for the next event on events
+func nextEvent(t *testing.T, events <-chan locator.Event) locator.Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatalf("expected an event, got none")
+		return locator.Event{}
+	}
+}
+
+// Test subscribers see registrations, overwrites, first instantiations and
+// unregistrations in order
+func TestSubscribe(t *testing.T) {
+	sl := locator.New()
+	events := subscribe(sl)
+
+	locator.RegisterSingleton(sl, &AnotherTestService{})
+	locator.RegisterSingleton(sl, &AnotherTestService{})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	for i := 0; i < 2; i++ {
+		if _, err := locator.Get[*TestService](sl); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	locator.Unregister[*AnotherTestService](sl)
+
+	expected := []struct {
+		op   locator.EventOp
+		typ  string
+		kind locator.Kind
+	}{
+		{locator.EventRegister, "*locator_test.AnotherTestService", locator.KindSingleton},
+		{locator.EventOverwrite, "*locator_test.AnotherTestService", locator.KindSingleton},
+		{locator.EventRegister, "*locator_test.TestService", locator.KindLazySingleton},
+		{locator.EventInstantiate, "*locator_test.TestService", locator.KindLazySingleton},
+		{locator.EventUnregister, "*locator_test.AnotherTestService", locator.KindUnregistered},
+	}
+	for i, want := range expected {
+		ev := nextEvent(t, events)
+		if ev.Op != want.op || ev.Type != want.typ || ev.Kind != want.kind {
+			t.Fatalf("expected event %d to be %v %s (%v), got %+v", i, want.op, want.typ, want.kind, ev)
+		}
+		if ev.Op == locator.EventRegister && ev.CallSite == "" {
+			t.Fatalf("expected a call site, got %+v", ev)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("expected no more events, got %+v", ev)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// Test subscribers can use the locator while events are delivered
+func TestSubscribeUsesLocator(t *testing.T) {
+	sl := locator.New()
+	counts := make(chan int, 10)
+	sl.Subscribe(func(locator.Event) { counts <- len(sl.Registrations()) })
+
+	locator.RegisterSingleton(sl, &TestService{})
+	select {
+	case n := <-counts:
+		if n != 1 {
+			t.Fatalf("expected 1 registration, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected an event, got none")
+	}
+}
+
+// Test overriding and restoring a registration is reported
+func TestSubscribeOverride(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterFactory(sl, func() *TestService { return &TestService{} })
+	events := subscribe(sl)
+
+	restore := locator.Override(sl, &TestService{})
+	restore()
+
+	if ev := nextEvent(t, events); ev.Op != locator.EventOverwrite || ev.Kind != locator.KindSingleton {
+		t.Fatalf("expected the override, got %+v", ev)
+	}
+	if ev := nextEvent(t, events); ev.Op != locator.EventOverwrite || ev.Kind != locator.KindFactory {
+		t.Fatalf("expected the restored factory, got %+v", ev)
+	}
+}
+
+// Test subscribers see tagged services and group members
+func TestSubscribeGroups(t *testing.T) {
+	sl := locator.New()
+	events := subscribe(sl)
+
+	locator.RegisterTagged[StartupHook](sl, namedHook("migrate"), "startup-hooks")
+	locator.RegisterInto[Handler, *EchoHandler](sl)
+
+	ev := nextEvent(t, events)
+	if ev.Op != locator.EventRegister || ev.Type != "locator_test.StartupHook[tag startup-hooks]" || ev.Kind != locator.KindSingleton {
+		t.Fatalf("expected the tagged service, got %+v", ev)
+	}
+	ev = nextEvent(t, events)
+	if ev.Op != locator.EventRegister || ev.Type != "locator_test.Handler[member *locator_test.EchoHandler]" || ev.CallSite == "" {
+		t.Fatalf("expected the group member, got %+v", ev)
+	}
+}
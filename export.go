@@ -0,0 +1,117 @@
+package locator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// graphNode is a type in the exported dependency graph
+type graphNode struct {
+	name string
+	// kind is the lifetime of the registration; registered is false for a
+	// dependency that is not registered
+	kind       Kind
+	registered bool
+	deps       []string
+}
+
+// exportGraph returns the nodes of the dependency graph sorted by name, including
+// unregistered dependencies
+func (sl *ServiceLocator) exportGraph() []graphNode {
+	graph := sl.DependencyGraph()
+	nodes := make(map[string]*graphNode, len(graph))
+	for _, info := range sl.Registrations() {
+		nodes[info.Type] = &graphNode{name: info.Type, kind: info.Kind, registered: true, deps: graph[info.Type]}
+	}
+	for _, deps := range graph {
+		for _, dep := range deps {
+			if nodes[dep] == nil {
+				nodes[dep] = &graphNode{name: dep}
+			}
+		}
+	}
+
+	sorted := make([]graphNode, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, *node)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// dotColors are the fill colors of DOT nodes by lifetime
+var dotColors = map[Kind]string{
+	KindSingleton:     "lightblue",
+	KindLazySingleton: "palegreen",
+	KindFactory:       "khaki",
+	KindCachedFactory: "orange",
+	KindScoped:        "plum",
+}
+
+// ExportDOT writes the dependency graph in Graphviz DOT format to w, with one
+// node per type labeled and colored by lifetime and an edge from each type to
+// every type its provider depends on. Dependencies that are not registered are
+// drawn dashed. The edges are those reported by DependencyGraph
+func (sl *ServiceLocator) ExportDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph locator {\n\tnode [shape=box, style=filled];\n")
+	nodes := sl.exportGraph()
+	for _, node := range nodes {
+		if !node.registered {
+			fmt.Fprintf(&b, "\t%s [label=%s, style=dashed];\n", strconv.Quote(node.name), strconv.Quote(node.name+"\nunregistered"))
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, fillcolor=%s];\n", strconv.Quote(node.name), strconv.Quote(node.name+"\n"+node.kind.String()), dotColors[node.kind])
+	}
+	for _, node := range nodes {
+		for _, dep := range node.deps {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(node.name), strconv.Quote(dep))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidStyles are the Mermaid class definitions matching the DOT colors
+const mermaidStyles = `	classDef singleton fill:#add8e6
+	classDef lazy fill:#98fb98
+	classDef factory fill:#f0e68c
+	classDef cached fill:#ffa500
+	classDef scoped fill:#dda0dd
+	classDef unregistered stroke-dasharray:5 5
+`
+
+// ExportMermaid writes the dependency graph as a Mermaid flowchart to w, which
+// GitHub and GitLab render in markdown inside a mermaid code block. Nodes and
+// edges are the same as those of ExportDOT
+func (sl *ServiceLocator) ExportMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	nodes := sl.exportGraph()
+	ids := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		ids[node.name] = fmt.Sprintf("n%d", i)
+	}
+	for _, node := range nodes {
+		class := "unregistered"
+		if node.registered {
+			class = node.kind.String()
+		}
+		label := strings.ReplaceAll(node.name, `"`, "#quot;")
+		fmt.Fprintf(&b, "\t%s[\"%s<br/>%s\"]:::%s\n", ids[node.name], label, class, class)
+	}
+	for _, node := range nodes {
+		for _, dep := range node.deps {
+			fmt.Fprintf(&b, "\t%s --> %s\n", ids[node.name], ids[dep])
+		}
+	}
+	b.WriteString(mermaidStyles)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
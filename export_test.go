@@ -0,0 +1,63 @@
+package locator_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// newExportLocator returns a locator whose graph has a lazy singleton depending
+// on a singleton and on an unregistered type
+func newExportLocator() *locator.ServiceLocator {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &ServiceB{})
+	locator.RegisterLazySingleton(sl, func() *ServiceA { return &ServiceA{} })
+	locator.DependsOn[*ServiceA](sl, reflect.TypeOf((*ServiceB)(nil)), reflect.TypeOf((*ServiceC)(nil)))
+	return sl
+}
+
+// Test ExportDOT writes nodes colored by lifetime and dependency edges
+func TestExportDOT(t *testing.T) {
+	var out strings.Builder
+	if err := newExportLocator().ExportDOT(&out); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := `digraph locator {
+	node [shape=box, style=filled];
+	"*locator_test.ServiceA" [label="*locator_test.ServiceA\nlazy", fillcolor=palegreen];
+	"*locator_test.ServiceB" [label="*locator_test.ServiceB\nsingleton", fillcolor=lightblue];
+	"*locator_test.ServiceC" [label="*locator_test.ServiceC\nunregistered", style=dashed];
+	"*locator_test.ServiceA" -> "*locator_test.ServiceB";
+	"*locator_test.ServiceA" -> "*locator_test.ServiceC";
+}
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+// Test ExportMermaid writes a flowchart with one class per lifetime
+func TestExportMermaid(t *testing.T) {
+	var out strings.Builder
+	if err := newExportLocator().ExportMermaid(&out); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := `flowchart LR
+	n0["*locator_test.ServiceA<br/>lazy"]:::lazy
+	n1["*locator_test.ServiceB<br/>singleton"]:::singleton
+	n2["*locator_test.ServiceC<br/>unregistered"]:::unregistered
+	n0 --> n1
+	n0 --> n2
+	classDef singleton fill:#add8e6
+	classDef lazy fill:#98fb98
+	classDef factory fill:#f0e68c
+	classDef cached fill:#ffa500
+	classDef scoped fill:#dda0dd
+	classDef unregistered stroke-dasharray:5 5
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
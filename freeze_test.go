@@ -0,0 +1,113 @@
+package locator_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// expectFrozenPanic fails the test unless register panics with ErrFrozen
+func expectFrozenPanic(t *testing.T, name string, register func()) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, locator.ErrFrozen) {
+			t.Fatalf("%s: expected a panic wrapping ErrFrozen, got %v", name, r)
+		}
+	}()
+	register()
+}
+
+// Test registration fails after Freeze while resolution keeps working
+func TestFreeze(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: "Lazy"}
+	})
+	sl.Freeze()
+
+	expectFrozenPanic(t, "RegisterSingleton", func() {
+		locator.RegisterSingleton(sl, 42)
+	})
+	expectFrozenPanic(t, "RegisterFactory", func() {
+		locator.RegisterFactory(sl, func() int { return 42 })
+	})
+	expectFrozenPanic(t, "Unregister", func() {
+		locator.Unregister[*TestService](sl)
+	})
+	expectFrozenPanic(t, "Reset", func() {
+		sl.Reset()
+	})
+	expectFrozenPanic(t, "RegisterDefaultSingleton", func() {
+		locator.RegisterDefaultSingleton(sl, 42)
+	})
+	expectFrozenPanic(t, "Decorate", func() {
+		locator.Decorate(sl, func(s *TestService) *TestService { return s })
+	})
+	if err := locator.RegisterVersioned(sl, "1.0.0", 42); !errors.Is(err, locator.ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from RegisterVersioned, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if service.Name != "Lazy" {
+			t.Fatalf("expected Lazy, got %v", service.Name)
+		}
+	}
+	if callCount != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount)
+	}
+
+	if _, err := locator.Get[int](sl); err == nil {
+		t.Fatalf("expected the rejected registration not to be stored")
+	}
+
+	// A clone of a frozen locator can be modified
+	clone := sl.Clone()
+	locator.RegisterSingleton(clone, 42)
+}
+
+// Test a frozen locator keeps reporting changes made through the operations it
+// still allows
+func TestFreezeSnapshot(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: "Lazy"}
+	})
+	locator.RegisterSingleton(sl, &AnotherTestService{ID: 1})
+	sl.Freeze()
+
+	var resolved int
+	sl.OnResolve(func(reflect.Type, any, time.Duration, error) { resolved++ })
+	locator.Get[*TestService](sl)
+	locator.Get[*TestService](sl)
+	sl.ResetInstances()
+	locator.Get[*TestService](sl)
+	if callCount != 2 {
+		t.Fatalf("expected the lazy singleton to be rebuilt after ResetInstances, got %d builds", callCount)
+	}
+	if resolved != 3 {
+		t.Fatalf("expected a hook added after Freeze to run 3 times, got %d", resolved)
+	}
+
+	if _, err := locator.Replace(sl, &AnotherTestService{ID: 2}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	another, err := locator.Get[*AnotherTestService](sl)
+	if err != nil || another.ID != 2 {
+		t.Fatalf("expected the replacement, got %v, %v", another, err)
+	}
+}
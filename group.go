@@ -0,0 +1,262 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RegisterImplementors registers each impl as a singleton under its concrete type
+// and adds it to the group of implementors of Iface, so a single call bootstraps a
+// set of plugins that can be resolved individually with Get or together with
+// ResolveImplementors. Implementors are kept in registration order. A nil impl has
+// no concrete type and is skipped, or panics with WithStrictRegistration
+func RegisterImplementors[Iface any](sl *ServiceLocator, impls ...Iface) {
+	groupKey := getGroupKey[Iface]()
+	valid := make([]Iface, 0, len(impls))
+	for _, impl := range impls {
+		if reflect.TypeOf(impl) != nil {
+			valid = append(valid, impl)
+		} else if sl.opts.strictRegistration {
+			panic(fmt.Errorf("nil implementor of %v", groupKey))
+		}
+	}
+	impls = valid
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(groupKey)
+	for _, impl := range impls {
+		typeKey := reflect.TypeOf(impl)
+		sl.mustAdmit(typeKey, KindSingleton, sl.isRegistered(typeKey))
+	}
+	for _, impl := range impls {
+		typeKey := reflect.TypeOf(impl)
+		delete(sl.providers, typeKey)
+		sl.storeInstance(typeKey, impl)
+	}
+
+	members := make([]groupMember[Iface], 0, len(impls))
+	for _, impl := range impls {
+		members = append(members, groupMember[Iface]{instance: impl})
+	}
+	addGroupMembers(sl, groupKey, members...)
+}
+
+// RegisterInto adds T to the set of implementations of I returned by GetAll. T
+// keeps its own registration, which decides how its instances are built, so
+// RegisterInto is typically paired with a registration of T. Registration hooks
+// and subscribers see T with the lifetime of its current registration. It panics
+// if T cannot be used as an I or a registration hook rejects it
+func RegisterInto[I, T any](sl *ServiceLocator) {
+	typeKey, iface := reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*I)(nil)).Elem()
+	if !typeKey.AssignableTo(iface) {
+		panic(fmt.Errorf("type %v does not implement %v", typeKey, iface))
+	}
+
+	groupKey := getGroupKey[I]()
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(groupKey)
+	if err := sl.announce(memberKey{group: iface, typ: typeKey}, sl.registeredKind(typeKey), false, callerSite()); err != nil {
+		panic(err)
+	}
+	addGroupMembers(sl, groupKey, groupMember[I]{typeKey: typeKey})
+}
+
+// memberKey identifies typ as a member of the group of the interface group, as
+// announced by RegisterInto
+type memberKey struct {
+	group reflect.Type
+	typ   reflect.Type
+}
+
+// String returns the group followed by the member type, for events
+func (k memberKey) String() string {
+	return fmt.Sprintf("%v[member %v]", k.group, k.typ)
+}
+
+// registeredKind returns the lifetime of the registration of typeKey,
+// KindUnregistered if it has none. The caller must hold sl.mu
+func (sl *ServiceLocator) registeredKind(typeKey any) Kind {
+	if r, ok := sl.providers[typeKey].(resolver); ok {
+		return r.kind()
+	}
+	if _, exists := sl.instances[typeKey]; exists {
+		return KindSingleton
+	}
+	return KindUnregistered
+}
+
+// GetAll resolves every implementation of I added with RegisterImplementors or
+// RegisterInto, in the order they were added, starting with those inherited by a
+// scope. Implementations added with RegisterInto are resolved through the locator
+// on each call. If any of them fails, GetAll returns every failure joined
+func GetAll[I any](sl *ServiceLocator) ([]I, error) {
+	members := groupMembers[I](sl)
+	all := make([]I, 0, len(members))
+	var errs []error
+	for _, member := range members {
+		if member.typeKey == nil {
+			all = append(all, member.instance)
+			continue
+		}
+		instance, found, err := sl.resolve(context.Background(), member.typeKey)
+		if !found {
+			err = sl.missingError(member.typeKey)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve %v for %v: %w", member.typeKey, getTypeKey[I](), err))
+			continue
+		}
+		all = append(all, castInstance[I](instance))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return all, nil
+}
+
+// FirstMatch returns the first implementation of I, in the order GetAll returns
+// them, for which pred is true, for chain of responsibility dispatch such as
+// finding the first handler that accepts a message. Implementations added with
+// RegisterInto are resolved one at a time, so none after the match is built, and
+// those that fail to resolve are skipped. It reports false if nothing matches
+func FirstMatch[I any](sl *ServiceLocator, pred func(I) bool) (I, bool) {
+	for _, member := range groupMembers[I](sl) {
+		candidate := member.instance
+		if member.typeKey != nil {
+			instance, found, err := sl.resolve(context.Background(), member.typeKey)
+			if !found || err != nil {
+				continue
+			}
+			candidate = castInstance[I](instance)
+		}
+		if pred(candidate) {
+			return candidate, true
+		}
+	}
+	var zero I
+	return zero, false
+}
+
+// ResolveImplementors returns every implementor registered for Iface with
+// RegisterImplementors in registration order, starting with those inherited by a
+// scope. The returned slice is a copy and may be modified freely
+func ResolveImplementors[Iface any](sl *ServiceLocator) []Iface {
+	var implementors []Iface
+	for _, member := range groupMembers[Iface](sl) {
+		if member.typeKey == nil {
+			implementors = append(implementors, member.instance)
+		}
+	}
+	return implementors
+}
+
+// groupMember is an element of the group of Iface: either an instance added by
+// RegisterImplementors or, when typeKey is set, a type added by RegisterInto
+type groupMember[Iface any] struct {
+	instance Iface
+	typeKey  reflect.Type
+}
+
+// resolve returns the instance of m, resolving it with ctx if it was added by
+// RegisterInto
+func (m groupMember[Iface]) resolve(ctx context.Context, sl *ServiceLocator) (Iface, error) {
+	if m.typeKey == nil {
+		return m.instance, nil
+	}
+	instance, found, err := sl.resolve(ctx, m.typeKey)
+	if !found {
+		err = sl.missingError(m.typeKey)
+	}
+	if err != nil {
+		var zero Iface
+		return zero, fmt.Errorf("resolve: %w", err)
+	}
+	return castInstance[Iface](instance), nil
+}
+
+// String names the concrete type of m, for error messages
+func (m groupMember[Iface]) String() string {
+	if m.typeKey != nil {
+		return m.typeKey.String()
+	}
+	return fmt.Sprintf("%T", m.instance)
+}
+
+// addGroupMembers appends members to the group under groupKey. The caller must
+// hold sl.mu
+func addGroupMembers[Iface any](sl *ServiceLocator, groupKey any, members ...groupMember[Iface]) {
+	existing, _ := sl.groups[groupKey].([]groupMember[Iface])
+	// Copy on append so slices returned earlier are never modified
+	sl.groups[groupKey] = append(existing[:len(existing):len(existing)], members...)
+}
+
+// groupMembers returns the members of the group of Iface, starting with those
+// inherited by a scope
+func groupMembers[Iface any](sl *ServiceLocator) []groupMember[Iface] {
+	var inherited []groupMember[Iface]
+	if sl.parent != nil {
+		inherited = groupMembers[Iface](sl.parent)
+	}
+
+	sl.mu.RLock()
+	members, _ := sl.groups[getGroupKey[Iface]()].([]groupMember[Iface])
+	sl.mu.RUnlock()
+
+	return append(inherited, members...)
+}
+
+// BroadcastCtx invokes call concurrently on every implementation of Iface, in the
+// order GetAll returns them, and waits for all of them or for ctx to be done,
+// whichever comes first. Implementations added with RegisterInto are resolved with
+// ctx by the goroutine calling them, and a failed resolution is reported instead of
+// the call. Errors are returned joined in registration order. Members that have not
+// finished when ctx is done are reported with a note wrapping ctx.Err(); their
+// calls are not waited for and should honor ctx themselves
+func BroadcastCtx[Iface any](ctx context.Context, sl *ServiceLocator, call func(ctx context.Context, member Iface) error) error {
+	members := groupMembers[Iface](sl)
+
+	type result struct {
+		index int
+		err   error
+	}
+	// Buffered so calls finishing after cancellation never block
+	results := make(chan result, len(members))
+	for i, member := range members {
+		go func(i int, member groupMember[Iface]) {
+			instance, err := member.resolve(ctx, sl)
+			if err == nil {
+				err = call(ctx, instance)
+			}
+			results <- result{index: i, err: err}
+		}(i, member)
+	}
+
+	finished := make([]bool, len(members))
+	errs := make([]error, len(members))
+	for remaining := len(members); remaining > 0; remaining-- {
+		select {
+		case r := <-results:
+			finished[r.index] = true
+			if r.err != nil {
+				errs[r.index] = fmt.Errorf("%s: %w", members[r.index], r.err)
+			}
+		case <-ctx.Done():
+			for i, member := range members {
+				if !finished[i] {
+					errs[i] = fmt.Errorf("%s: did not finish before cancellation: %w", member, ctx.Err())
+				}
+			}
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getGroupKey returns the key of the group for Iface
+func getGroupKey[Iface any]() any {
+	return getTypeKey[Iface]()
+}
@@ -0,0 +1,243 @@
+package locator_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type Handler interface {
+	Handle(msg string) string
+}
+
+type UpperHandler struct{}
+
+func (*UpperHandler) Handle(msg string) string { return "upper:" + msg }
+
+type LowerHandler struct{}
+
+func (*LowerHandler) Handle(msg string) string { return "lower:" + msg }
+
+type EchoHandler struct{}
+
+func (*EchoHandler) Handle(msg string) string { return "echo:" + msg }
+
+// Test RegisterImplementors registers individually and as a group
+func TestRegisterImplementors(t *testing.T) {
+	sl := locator.New()
+
+	upper, lower, echo := &UpperHandler{}, &LowerHandler{}, &EchoHandler{}
+	locator.RegisterImplementors[Handler](sl, upper, lower, echo)
+
+	handlers := locator.ResolveImplementors[Handler](sl)
+	if len(handlers) != 3 {
+		t.Fatalf("expected 3 handlers, got %d", len(handlers))
+	}
+	expected := []string{"upper:x", "lower:x", "echo:x"}
+	for i, handler := range handlers {
+		if got := handler.Handle("x"); got != expected[i] {
+			t.Fatalf("expected %s at position %d, got %s", expected[i], i, got)
+		}
+	}
+
+	resolvedLower, err := locator.Get[*LowerHandler](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolvedLower != lower {
+		t.Fatalf("expected the registered implementor")
+	}
+
+	// Mutating the returned slice must not affect the group
+	handlers[0] = nil
+	if locator.ResolveImplementors[Handler](sl)[0] == nil {
+		t.Fatalf("expected the group to be unaffected by changes to a returned slice")
+	}
+
+	if others := locator.ResolveImplementors[Greeter](sl); len(others) != 0 {
+		t.Fatalf("expected no implementors for another interface, got %d", len(others))
+	}
+}
+
+// Test BroadcastCtx reports members that did not finish before cancellation
+func TestBroadcastCtx(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterImplementors[Handler](sl, &UpperHandler{}, &LowerHandler{}, &EchoHandler{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	var completed atomic.Int32
+	err := locator.BroadcastCtx(ctx, sl, func(ctx context.Context, h Handler) error {
+		switch h.(type) {
+		case *LowerHandler:
+			completed.Add(1)
+			return errors.New("lower failed")
+		case *EchoHandler:
+			<-release
+		}
+		completed.Add(1)
+		return nil
+	})
+
+	if completed.Load() != 2 {
+		t.Fatalf("expected 2 completed members, got %d", completed.Load())
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error wrapping context.DeadlineExceeded, got %v", err)
+	}
+	for _, note := range []string{"*locator_test.LowerHandler: lower failed", "*locator_test.EchoHandler: did not finish before cancellation"} {
+		if !strings.Contains(err.Error(), note) {
+			t.Fatalf("expected error containing %q, got %v", note, err)
+		}
+	}
+	if strings.Contains(err.Error(), "UpperHandler") {
+		t.Fatalf("expected no error for UpperHandler, got %v", err)
+	}
+}
+
+// Test GetAll resolves implementations added either way, in the order they were added
+func TestGetAll(t *testing.T) {
+	sl := locator.New()
+
+	var built int
+	locator.RegisterLazySingleton(sl, func() *LowerHandler {
+		built++
+		return &LowerHandler{}
+	})
+	locator.RegisterFactory(sl, func() *EchoHandler { return &EchoHandler{} })
+
+	locator.RegisterInto[Handler, *LowerHandler](sl)
+	locator.RegisterImplementors[Handler](sl, &UpperHandler{})
+	locator.RegisterInto[Handler, *EchoHandler](sl)
+	if built != 0 {
+		t.Fatalf("expected no construction before GetAll, got %d", built)
+	}
+
+	handlers, err := locator.GetAll[Handler](sl.Scope())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var got []string
+	for _, h := range handlers {
+		got = append(got, h.Handle("x"))
+	}
+	expected := []string{"lower:x", "upper:x", "echo:x"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if implementors := locator.ResolveImplementors[Handler](sl); len(implementors) != 1 {
+		t.Fatalf("expected only the RegisterImplementors member, got %d", len(implementors))
+	}
+}
+
+// Test FirstMatch returns the first matching handler without building later ones
+func TestFirstMatch(t *testing.T) {
+	sl := locator.New()
+
+	lower := &LowerHandler{}
+	locator.RegisterImplementors[Handler](sl, &UpperHandler{}, lower)
+	var echoBuilt bool
+	locator.RegisterLazySingleton(sl, func() *EchoHandler {
+		echoBuilt = true
+		return &EchoHandler{}
+	})
+	locator.RegisterInto[Handler, *EchoHandler](sl)
+
+	handler, ok := locator.FirstMatch(sl, func(h Handler) bool {
+		return strings.HasPrefix(h.Handle("x"), "lower")
+	})
+	if !ok || handler != lower {
+		t.Fatalf("expected the lower handler, got %v, %v", handler, ok)
+	}
+	if echoBuilt {
+		t.Fatalf("expected handlers after the match not to be built")
+	}
+
+	if handler, ok := locator.FirstMatch(sl, func(Handler) bool { return false }); ok {
+		t.Fatalf("expected no match, got %v", handler)
+	}
+}
+
+// Test GetAll reports members that cannot be resolved and RegisterInto rejects
+// types that do not implement the interface
+func TestGetAllErrors(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterInto[Handler, *UpperHandler](sl)
+
+	_, err := locator.GetAll[Handler](sl)
+	if err == nil || !strings.Contains(err.Error(), "no provider registered for type *locator_test.UpperHandler") {
+		t.Fatalf("expected missing registration error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for a type not implementing Handler")
+		}
+	}()
+	locator.RegisterInto[Handler, *TestService](sl)
+}
+
+// Test nil implementors are skipped like nil providers
+func TestRegisterImplementorsNil(t *testing.T) {
+	sl := locator.New()
+	upper := &UpperHandler{}
+	locator.RegisterImplementors[Handler](sl, nil, upper)
+
+	handlers := locator.ResolveImplementors[Handler](sl)
+	if len(handlers) != 1 || handlers[0] != upper {
+		t.Fatalf("expected only the upper handler, got %v", handlers)
+	}
+	if infos := sl.Registrations(); len(infos) != 1 || infos[0].Type != "*locator_test.UpperHandler" {
+		t.Fatalf("expected only the upper handler to be registered, got %+v", infos)
+	}
+
+	strict := locator.New(locator.WithStrictRegistration())
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !strings.Contains(err.Error(), "nil implementor of locator_test.Handler") {
+			t.Fatalf("expected nil implementor panic, got %v", err)
+		}
+		if handlers := locator.ResolveImplementors[Handler](strict); len(handlers) != 0 {
+			t.Fatalf("expected nothing registered, got %v", handlers)
+		}
+	}()
+	locator.RegisterImplementors[Handler](strict, upper, nil)
+}
+
+// Test BroadcastCtx reaches members added with RegisterInto and reports those that
+// cannot be resolved
+func TestBroadcastCtxRegisterInto(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterImplementors[Handler](sl, &UpperHandler{})
+	locator.RegisterLazySingleton(sl, func() *EchoHandler { return &EchoHandler{} })
+	locator.RegisterInto[Handler, *EchoHandler](sl)
+	locator.RegisterInto[Handler, *LowerHandler](sl)
+
+	var mu sync.Mutex
+	var handled []string
+	err := locator.BroadcastCtx(context.Background(), sl, func(ctx context.Context, h Handler) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, h.Handle("ping"))
+		return nil
+	})
+
+	sort.Strings(handled)
+	if !reflect.DeepEqual(handled, []string{"echo:ping", "upper:ping"}) {
+		t.Fatalf("expected both resolvable members called, got %v", handled)
+	}
+	if err == nil || !strings.Contains(err.Error(), "*locator_test.LowerHandler: resolve: no provider registered for type *locator_test.LowerHandler") {
+		t.Fatalf("expected the unresolvable member reported, got %v", err)
+	}
+}
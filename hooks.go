@@ -0,0 +1,92 @@
+package locator
+
+import (
+	"reflect"
+	"time"
+)
+
+// ResolveHook is called after a resolution with the resolved type, the instance,
+// the time the resolution took and its error. instance is nil when err is not
+type ResolveHook func(t reflect.Type, instance any, d time.Duration, err error)
+
+// OnResolve installs hook to be called after every resolution, including those
+// made by providers for their dependencies. Hooks run in installation order once
+// the locator has released its locks, so they may resolve from it. Hooks installed
+// on a locator also run for resolutions made through its scopes
+func (sl *ServiceLocator) OnResolve(hook ResolveHook) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	// Copy on append so a resolution in progress keeps the hooks it started with
+	sl.onResolve = append(sl.onResolve[:len(sl.onResolve):len(sl.onResolve)], hook)
+	sl.changed()
+}
+
+// resolveHooks returns the hooks of sl and its ancestors, ancestors first
+func (sl *ServiceLocator) resolveHooks() []ResolveHook {
+	var hooks []ResolveHook
+	if sl.parent != nil {
+		hooks = sl.parent.resolveHooks()
+	}
+
+	// The snapshot's hooks are capped so that a scope appending its own always copies
+	onResolve := sl.view().onResolve
+	if hooks == nil {
+		return onResolve
+	}
+	return append(hooks, onResolve...)
+}
+
+// keyType returns the type resolved for typeKey
+func keyType(typeKey any) reflect.Type {
+	switch key := typeKey.(type) {
+	case reflect.Type:
+		return key
+	case keyedKey:
+		return key.typ
+	case taggedKey:
+		return key.typ
+	case memberKey:
+		return key.typ
+	default:
+		return reflect.TypeOf(typeKey)
+	}
+}
+
+// RegisterEvent describes a registration reported to a RegisterHook
+type RegisterEvent struct {
+	// Type is the registered type
+	Type reflect.Type
+	// Kind is the lifetime of the registration
+	Kind Kind
+	// CallSite is the file:line of the registering call, empty if unknown
+	CallSite string
+	// Overwrite reports whether the type already had a registration
+	Overwrite bool
+}
+
+// RegisterHook is called before a registration is stored. Returning an error
+// rejects the registration, which then panics with it, or fails with it for
+// registration functions that return an error
+type RegisterHook func(ev RegisterEvent) error
+
+// OnRegister installs hook to be called whenever a type is registered or
+// overwritten, for example to log late registrations or reject them in production.
+// Hooks run in installation order while the locator is locked, so they must not
+// use it. Hooks installed on a locator also run for registrations on its scopes
+func (sl *ServiceLocator) OnRegister(hook RegisterHook) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.onRegister = append(sl.onRegister[:len(sl.onRegister):len(sl.onRegister)], hook)
+}
+
+// registerHooks returns the registration hooks of sl and its ancestors, ancestors
+// first. The caller must hold sl.mu
+func (sl *ServiceLocator) registerHooks() []RegisterHook {
+	hooks := sl.onRegister
+	for p := sl.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		hooks = append(p.onRegister[:len(p.onRegister):len(p.onRegister)], hooks...)
+		p.mu.RUnlock()
+	}
+	return hooks
+}
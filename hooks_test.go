@@ -0,0 +1,174 @@
+package locator_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// resolution records a call to a resolve hook
+type resolution struct {
+	typ      reflect.Type
+	instance any
+	err      error
+}
+
+// Test OnResolve hooks see every resolution, including nested and failed ones
+func TestOnResolve(t *testing.T) {
+	sl := locator.New()
+
+	var calls []resolution
+	sl.OnResolve(func(typ reflect.Type, instance any, d time.Duration, err error) {
+		calls = append(calls, resolution{typ: typ, instance: instance, err: err})
+	})
+
+	service := &TestService{Name: "dep"}
+	locator.RegisterSingleton(sl, service)
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *AnotherTestService {
+		locator.Get[*TestService](sl)
+		return &AnotherTestService{}
+	})
+
+	if _, err := locator.Get[*AnotherTestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 hook calls, got %d", len(calls))
+	}
+	if calls[0].typ != reflect.TypeOf(service) || calls[0].instance != service {
+		t.Fatalf("expected the nested resolution first, got %+v", calls[0])
+	}
+	if calls[1].typ != reflect.TypeOf(&AnotherTestService{}) || calls[1].err != nil {
+		t.Fatalf("expected the outer resolution last, got %+v", calls[1])
+	}
+
+	locator.Get[string](sl)
+	last := calls[len(calls)-1]
+	if last.typ != reflect.TypeOf("") || last.err == nil || !strings.Contains(last.err.Error(), "no provider registered") {
+		t.Fatalf("expected a missing registration error, got %+v", last)
+	}
+}
+
+// Test hooks installed on a locator run once for resolutions made through its scopes
+func TestOnResolveScope(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{})
+
+	var parentCalls, scopeCalls int
+	sl.OnResolve(func(reflect.Type, any, time.Duration, error) { parentCalls++ })
+	scope := sl.Scope()
+	scope.OnResolve(func(reflect.Type, any, time.Duration, error) { scopeCalls++ })
+
+	if _, err := locator.Get[*TestService](scope); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parentCalls != 1 || scopeCalls != 1 {
+		t.Fatalf("expected one call per hook, got %d and %d", parentCalls, scopeCalls)
+	}
+
+	if _, err := locator.Get[*TestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parentCalls != 2 || scopeCalls != 1 {
+		t.Fatalf("expected only the parent hook to run, got %d and %d", parentCalls, scopeCalls)
+	}
+}
+
+// Test OnRegister hooks see registrations with their kind and call site
+func TestOnRegister(t *testing.T) {
+	sl := locator.New()
+
+	var events []locator.RegisterEvent
+	sl.OnRegister(func(ev locator.RegisterEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+
+	locator.RegisterSingleton(sl, &TestService{})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != reflect.TypeOf(&TestService{}) || events[0].Kind != locator.KindSingleton || events[0].Overwrite {
+		t.Fatalf("expected a new singleton, got %+v", events[0])
+	}
+	if events[1].Kind != locator.KindLazySingleton || !events[1].Overwrite {
+		t.Fatalf("expected a lazy singleton overwrite, got %+v", events[1])
+	}
+	if !strings.Contains(events[1].CallSite, "hooks_test.go:") {
+		t.Fatalf("expected call site in hooks_test.go, got %q", events[1].CallSite)
+	}
+}
+
+// Test a registration hook can reject registrations, including on scopes
+func TestOnRegisterReject(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "boot"})
+
+	errLate := errors.New("registration after startup")
+	sl.OnRegister(func(ev locator.RegisterEvent) error {
+		return errLate
+	})
+
+	scope := sl.Scope()
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, errLate) {
+			t.Fatalf("expected panic with %v, got %v", errLate, err)
+		}
+		instance, _ := locator.Get[*TestService](scope)
+		if instance.Name != "boot" {
+			t.Fatalf("expected the rejected registration not to be stored, got %v", instance.Name)
+		}
+		if err := locator.RegisterVersioned(sl, "1.0.0", &TestService{}); !errors.Is(err, errLate) {
+			t.Fatalf("expected %v, got %v", errLate, err)
+		}
+	}()
+	locator.RegisterSingleton(scope, &TestService{Name: "late"})
+}
+
+// Test OnRegister hooks see tagged services and group members, and can reject them
+func TestOnRegisterGroups(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterFactory(sl, func() *EchoHandler { return &EchoHandler{} })
+
+	var events []locator.RegisterEvent
+	sl.OnRegister(func(ev locator.RegisterEvent) error {
+		events = append(events, ev)
+		return nil
+	})
+	locator.RegisterTagged[StartupHook](sl, func() StartupHook { return namedHook("warm") }, "startup-hooks", "cache")
+	locator.RegisterInto[Handler, *EchoHandler](sl)
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	hookType := reflect.TypeOf((*StartupHook)(nil)).Elem()
+	if events[0].Type != hookType || events[0].Kind != locator.KindLazySingleton || events[1].Type != hookType {
+		t.Fatalf("expected one lazy tagged service per tag, got %+v", events[:2])
+	}
+	if events[2].Type != reflect.TypeOf(&EchoHandler{}) || events[2].Kind != locator.KindFactory {
+		t.Fatalf("expected the member with its factory kind, got %+v", events[2])
+	}
+	if !strings.Contains(events[2].CallSite, "hooks_test.go:") {
+		t.Fatalf("expected call site in hooks_test.go, got %q", events[2].CallSite)
+	}
+
+	errClosed := errors.New("closed group")
+	sl.OnRegister(func(ev locator.RegisterEvent) error { return errClosed })
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, errClosed) {
+			t.Fatalf("expected panic with %v, got %v", errClosed, err)
+		}
+		if hooks, _ := locator.GetByTag[StartupHook](sl, "late"); len(hooks) != 0 {
+			t.Fatalf("expected the rejected service not to be tagged, got %v", hooks)
+		}
+	}()
+	locator.RegisterTagged[StartupHook](sl, namedHook("late"), "late")
+}
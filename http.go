@@ -0,0 +1,17 @@
+package locator
+
+import "net/http"
+
+// ScopeMiddleware returns net/http middleware that opens a scope of sl for each
+// request, stores it in the request context for FromContext, and closes it once
+// the handler returns. Errors from closing the scope are discarded, so scoped
+// types should report their own Close failures
+func ScopeMiddleware(sl *ServiceLocator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := sl.Scope()
+			defer scope.Close()
+			next.ServeHTTP(w, r.WithContext(ContextWithLocator(r.Context(), scope)))
+		})
+	}
+}
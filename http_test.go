@@ -0,0 +1,50 @@
+package locator_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test the middleware gives each request its own scope and closes it
+func TestScopeMiddleware(t *testing.T) {
+	sl := locator.New()
+	var created []*UnitOfWork
+	locator.RegisterScoped(sl, func() *UnitOfWork {
+		uow := &UnitOfWork{ID: len(created) + 1}
+		created = append(created, uow)
+		return uow
+	})
+
+	handler := locator.ScopeMiddleware(sl)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := locator.FromContext(r.Context())
+		if !ok {
+			t.Errorf("expected a scope in the request context")
+			return
+		}
+		first, _ := locator.Get[*UnitOfWork](scope)
+		second, err := locator.Get[*UnitOfWork](scope)
+		if err != nil || first != second {
+			t.Errorf("expected one unit of work per request, got %v", err)
+			return
+		}
+		fmt.Fprint(w, first.ID)
+	}))
+
+	for i := 1; i <= 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if body := rec.Body.String(); body != fmt.Sprint(i) {
+			t.Fatalf("expected unit of work %d, got %s", i, body)
+		}
+	}
+
+	for _, uow := range created {
+		if !uow.closed {
+			t.Fatalf("expected unit of work %d to be closed after its request", uow.ID)
+		}
+	}
+}
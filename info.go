@@ -0,0 +1,79 @@
+package locator
+
+import (
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+)
+
+// SingletonInfo describes the first materialization of a lazy singleton
+type SingletonInfo struct {
+	// Labels are the pprof labels of the context passed to GetCtx, such as those
+	// set with pprof.Do
+	Labels map[string]string
+	// Function, File and Line locate the first caller outside the locator
+	Function string
+	File     string
+	Line     int
+	// Time is when construction finished
+	Time time.Time
+}
+
+// Info returns how the lazy singleton registered for T was first materialized.
+// It reports false if T is not a lazy singleton, has not been built yet, or the
+// locator was not created with WithGoroutineLabels
+func Info[T any](sl *ServiceLocator) (SingletonInfo, bool) {
+	sl.mu.RLock()
+	ls, ok := sl.providers[getTypeKey[T]()].(*lazySingleton[T])
+	sl.mu.RUnlock()
+	if !ok {
+		return SingletonInfo{}, false
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.info == nil {
+		return SingletonInfo{}, false
+	}
+	info := *ls.info
+	info.Labels = make(map[string]string, len(ls.info.Labels))
+	for key, value := range ls.info.Labels {
+		info.Labels[key] = value
+	}
+	return info, true
+}
+
+// newSingletonInfo captures the labels of ctx and the caller that triggered the
+// resolution
+func newSingletonInfo(ctx context.Context) *SingletonInfo {
+	info := &SingletonInfo{Labels: make(map[string]string), Time: time.Now()}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		info.Labels[key] = value
+		return true
+	})
+
+	frame := externalCaller()
+	info.Function, info.File, info.Line = frame.Function, frame.File, frame.Line
+	return info
+}
+
+// externalCaller returns the innermost frame of the calling goroutine outside the
+// locator package, or an empty frame if there is none
+func externalCaller() runtime.Frame {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			return frame
+		}
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
+
+// packagePath is the import path of this package, used to skip its own frames
+const packagePath = "github.com/RobinHood3082/locator"
@@ -0,0 +1,62 @@
+package locator_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Info records the labels and caller of the first materialization
+func TestInfo(t *testing.T) {
+	sl := locator.New(locator.WithGoroutineLabels())
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		return &TestService{Name: "Labeled"}
+	})
+
+	if _, ok := locator.Info[*TestService](sl); ok {
+		t.Fatalf("expected no info before materialization")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pprof.Do(context.Background(), pprof.Labels("worker", "init-1"), func(ctx context.Context) {
+			if _, err := locator.GetCtx[*TestService](ctx, sl); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}()
+	wg.Wait()
+
+	// Later resolutions from other goroutines do not change the record
+	locator.GetCtx[*TestService](pprof.WithLabels(context.Background(), pprof.Labels("worker", "late")), sl)
+
+	info, ok := locator.Info[*TestService](sl)
+	if !ok {
+		t.Fatalf("expected info after materialization")
+	}
+	if info.Labels["worker"] != "init-1" {
+		t.Fatalf("expected label init-1, got %v", info.Labels)
+	}
+	if !strings.HasSuffix(info.File, "info_test.go") || !strings.Contains(info.Function, "TestInfo") {
+		t.Fatalf("expected caller in TestInfo, got %s at %s:%d", info.Function, info.File, info.Line)
+	}
+}
+
+// Test Info is not recorded without WithGoroutineLabels
+func TestInfoDisabled(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		return &TestService{}
+	})
+	locator.Get[*TestService](sl)
+
+	if _, ok := locator.Info[*TestService](sl); ok {
+		t.Fatalf("expected no info without WithGoroutineLabels")
+	}
+}
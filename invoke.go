@@ -0,0 +1,83 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// errorType is the reflect.Type of the error interface
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Invoke calls fn with each of its parameters resolved from the locator by type,
+// for example to run a startup function taking a database and a configuration.
+// If fn's last result is an error it is returned; other results are discarded.
+// fn is not called if any parameter cannot be resolved, and every failure is
+// reported in the returned error
+func (sl *ServiceLocator) Invoke(fn any) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return fmt.Errorf("invoke: %T is not a function", fn)
+	}
+	t := v.Type()
+	if t.IsVariadic() {
+		return fmt.Errorf("invoke: variadic function %v is not supported", t)
+	}
+
+	args, err := sl.resolveArgs(context.Background(), t)
+	if err != nil {
+		return fmt.Errorf("invoke %v: %w", t, err)
+	}
+
+	results := v.Call(args)
+	if n := t.NumOut(); n > 0 && t.Out(n-1) == errorType {
+		err, _ := results[n-1].Interface().(error)
+		return err
+	}
+	return nil
+}
+
+// resolveArgs resolves the parameters of the function type t
+func (sl *ServiceLocator) resolveArgs(ctx context.Context, t reflect.Type) ([]reflect.Value, error) {
+	args := make([]reflect.Value, t.NumIn())
+	var errs []error
+	for i := range args {
+		arg, err := sl.resolveValue(ctx, t.In(i))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("argument %d: %w", i, err))
+			continue
+		}
+		args[i] = arg
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return args, nil
+}
+
+// resolveValue resolves typ as a value assignable to typ, mapping a nil instance
+// to the zero value
+func (sl *ServiceLocator) resolveValue(ctx context.Context, typ reflect.Type) (reflect.Value, error) {
+	if elem, ok := optionalElem(typ); ok {
+		return sl.resolveOptional(ctx, elem, typ)
+	}
+	value, found, err := sl.resolveKeyValue(ctx, typ, typ)
+	if !found {
+		err = sl.missingError(typ)
+	}
+	return value, err
+}
+
+// resolveKeyValue resolves the registration under typeKey as a value assignable
+// to typ, mapping a nil instance to the zero value
+func (sl *ServiceLocator) resolveKeyValue(ctx context.Context, typeKey any, typ reflect.Type) (reflect.Value, bool, error) {
+	instance, found, err := sl.resolve(ctx, typeKey)
+	if !found || err != nil {
+		return reflect.Value{}, found, err
+	}
+	if instance == nil {
+		return reflect.Zero(typ), true, nil
+	}
+	return reflect.ValueOf(instance), true, nil
+}
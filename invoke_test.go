@@ -0,0 +1,50 @@
+package locator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Invoke resolves every parameter and returns the function's error
+func TestInvoke(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+	locator.RegisterAs[Greeter](sl, baseGreeter{})
+
+	var got string
+	err := sl.Invoke(func(service *TestService, greeter Greeter) {
+		got = greeter.Greet() + " " + service.Name
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != "hello Service" {
+		t.Fatalf("expected hello Service, got %s", got)
+	}
+
+	errStartup := errors.New("migration failed")
+	if err := sl.Invoke(func(*TestService) error { return errStartup }); !errors.Is(err, errStartup) {
+		t.Fatalf("expected %v, got %v", errStartup, err)
+	}
+}
+
+// Test Invoke reports unresolvable parameters without calling the function
+func TestInvokeErrors(t *testing.T) {
+	sl := locator.New()
+
+	var called bool
+	err := sl.Invoke(func(*TestService, string, *AnotherTestService) { called = true })
+	if called {
+		t.Fatalf("expected the function not to be called")
+	}
+	if err == nil || strings.Count(err.Error(), "no provider registered") != 3 {
+		t.Fatalf("expected 3 missing parameters, got %v", err)
+	}
+
+	if err := sl.Invoke("not a function"); err == nil || err.Error() != "invoke: string is not a function" {
+		t.Fatalf("expected not a function error, got %v", err)
+	}
+}
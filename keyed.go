@@ -0,0 +1,52 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RegisterKeyedFactory registers a factory for T under key. Keys can be any
+// comparable value, so several providers of the same type can be told apart by a
+// tenant ID, an enum or a struct. Keys of different types never collide, even when
+// their values are equal
+func RegisterKeyedFactory[K comparable, T any](sl *ServiceLocator, key K, provider Provider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	sl.registerResolver(newKeyedKey[T](key), provider, false)
+}
+
+// GetKeyed retrieves an instance of the requested type registered under key
+func GetKeyed[K comparable, T any](sl *ServiceLocator, key K) (T, error) {
+	if instance, ok := sl.cached(newKeyedKey[T](key)); ok {
+		return castInstance[T](instance), nil
+	}
+	var zero T
+	typeKey := newKeyedKey[T](key)
+	instance, found, err := sl.resolve(context.Background(), typeKey)
+	if !found {
+		return zero, notRegistered(typeKey)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return castInstance[T](instance), nil
+}
+
+// keyedKey identifies a registration of typ under a runtime key. key holds the
+// key with its static type, so equal values of different key types differ
+type keyedKey struct {
+	typ reflect.Type
+	key any
+}
+
+// newKeyedKey returns the registration key of T under key
+func newKeyedKey[T any](key any) keyedKey {
+	return keyedKey{typ: getTypeKey[T]().(reflect.Type), key: key}
+}
+
+// String returns the type followed by the key, for error messages and graphs
+func (k keyedKey) String() string {
+	return fmt.Sprintf("%v[%T(%v)]", k.typ, k.key, k.key)
+}
@@ -0,0 +1,89 @@
+package locator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type Tenant struct {
+	ID int
+}
+
+type tenantID int
+
+type regionTenant struct {
+	Region string
+	ID     int
+}
+
+// Test keyed factories resolve by arbitrary comparable keys
+func TestKeyedFactory(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterKeyedFactory(sl, 1, func() *Tenant { return &Tenant{ID: 1} })
+	locator.RegisterKeyedFactory(sl, 2, func() *Tenant { return &Tenant{ID: 2} })
+	locator.RegisterKeyedFactory(sl, regionTenant{"eu", 3}, func() *Tenant { return &Tenant{ID: 3} })
+
+	for _, id := range []int{1, 2} {
+		tenant, err := locator.GetKeyed[int, *Tenant](sl, id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if tenant.ID != id {
+			t.Fatalf("expected tenant %d, got %d", id, tenant.ID)
+		}
+	}
+
+	tenant, err := locator.GetKeyed[regionTenant, *Tenant](sl, regionTenant{"eu", 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tenant.ID != 3 {
+		t.Fatalf("expected tenant 3, got %d", tenant.ID)
+	}
+
+	// Keyed registrations do not register the plain type
+	if _, err := locator.Get[*Tenant](sl); err == nil {
+		t.Fatalf("expected error for unkeyed type, got nil")
+	}
+}
+
+// Test keys of different types do not collide when their values are equal
+func TestKeyedFactoryKeyTypes(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterKeyedFactory(sl, 1, func() *Tenant { return &Tenant{ID: 1} })
+	locator.RegisterKeyedFactory(sl, tenantID(1), func() *Tenant { return &Tenant{ID: 100} })
+
+	tenant, err := locator.GetKeyed[tenantID, *Tenant](sl, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tenant.ID != 100 {
+		t.Fatalf("expected tenant 100, got %d", tenant.ID)
+	}
+
+	_, err = locator.GetKeyed[int64, *Tenant](sl, 1)
+	if err == nil || !strings.Contains(err.Error(), "int64(1)") {
+		t.Fatalf("expected error naming the int64 key, got %v", err)
+	}
+}
+
+// Test a nil keyed factory is rejected like any other nil provider
+func TestKeyedFactoryNil(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterKeyedFactory[string, *Tenant](sl, "acme", nil)
+	if _, err := locator.GetKeyed[string, *Tenant](sl, "acme"); err == nil || !strings.Contains(err.Error(), "no provider registered") {
+		t.Fatalf("expected the key to stay unregistered, got %v", err)
+	}
+
+	strict := locator.New(locator.WithStrictRegistration())
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected nil provider panic, got nil")
+		}
+	}()
+	locator.RegisterKeyedFactory[string, *Tenant](strict, "acme", nil)
+}
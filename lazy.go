@@ -0,0 +1,50 @@
+package locator
+
+import (
+	"context"
+	"sync"
+)
+
+// LazyHandle defers the resolution of T until Value is first called. It is named
+// LazyHandle because Lazy already builds a lazy singleton Registration
+type LazyHandle[T any] struct {
+	mu       sync.Mutex
+	sl       *ServiceLocator
+	done     bool
+	instance T
+}
+
+// GetLazy returns a handle resolving T on first use, so a service can depend on an
+// expensive one without forcing its construction. Nothing is resolved by GetLazy
+// itself, though a provider calling it is recorded as depending on T
+func GetLazy[T any](sl *ServiceLocator) *LazyHandle[T] {
+	if sl.frame != nil {
+		sl.recordEdge(sl.frame.typeKey, getTypeKey[T]())
+	}
+	// The handle resolves outside of the provider that created it, so it must not
+	// inherit the chain of types that provider was building
+	return &LazyHandle[T]{sl: &ServiceLocator{registry: sl.registry}}
+}
+
+// Value resolves T on the first call and returns the same instance afterwards.
+// A failed resolution is not remembered, so the next call tries again
+func (h *LazyHandle[T]) Value() (T, error) {
+	return h.ValueCtx(context.Background())
+}
+
+// ValueCtx is like Value but passes ctx to providers that depend on the
+// resolution context
+func (h *LazyHandle[T]) ValueCtx(ctx context.Context) (T, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.done {
+		return h.instance, nil
+	}
+
+	instance, err := GetCtx[T](ctx, h.sl)
+	if err != nil {
+		return instance, err
+	}
+	h.instance, h.done = instance, true
+	return instance, nil
+}
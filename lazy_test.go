@@ -0,0 +1,75 @@
+package locator_test
+
+import (
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test GetLazy defers construction until Value is called
+func TestGetLazy(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterFactory(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: "expensive"}
+	})
+
+	handle := locator.GetLazy[*TestService](sl)
+	if callCount != 0 {
+		t.Fatalf("expected no construction, got %d provider calls", callCount)
+	}
+
+	first, err := handle.Value()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, _ := handle.Value()
+	if first != second || callCount != 1 {
+		t.Fatalf("expected the handle to keep its instance, got %d provider calls", callCount)
+	}
+}
+
+// Test a failed resolution is retried by the next Value call
+func TestGetLazyRetry(t *testing.T) {
+	sl := locator.New()
+	handle := locator.GetLazy[*TestService](sl)
+
+	if _, err := handle.Value(); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	locator.RegisterSingleton(sl, &TestService{Name: "late"})
+	instance, err := handle.Value()
+	if err != nil || instance.Name != "late" {
+		t.Fatalf("expected late, got %v, %v", instance, err)
+	}
+}
+
+// Test a lazy handle lets two services depend on each other
+func TestGetLazyBreaksCycle(t *testing.T) {
+	sl := locator.New()
+
+	type serviceA struct {
+		b *locator.LazyHandle[*ServiceB]
+	}
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *serviceA {
+		return &serviceA{b: locator.GetLazy[*ServiceB](sl)}
+	})
+	var errA error
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceB {
+		_, errA = locator.Get[*serviceA](sl)
+		return &ServiceB{}
+	})
+
+	a, err := locator.Get[*serviceA](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := a.b.Value(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if errA != nil {
+		t.Fatalf("expected ServiceB to resolve the built serviceA, got %v", errA)
+	}
+}
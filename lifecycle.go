@@ -0,0 +1,103 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Starter is implemented by services that run in the background once started,
+// such as servers and consumers
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by services that must be stopped, usually the
+// counterpart of a Starter
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Start calls Start(ctx) on the instantiated services implementing Starter in the
+// reverse of ShutdownOrder, so every service starts after the services it depends
+// on. Lazy singletons not built yet are not started. If a service fails to start,
+// the services already started are stopped in reverse order and the start error
+// is returned together with the stop errors
+func (sl *ServiceLocator) Start(ctx context.Context) error {
+	typeKeys, instances := sl.lifecycleOrder()
+
+	var started []int
+	for i := len(instances) - 1; i >= 0; i-- {
+		starter, ok := instances[i].(Starter)
+		if !ok {
+			continue
+		}
+		err := ctx.Err()
+		if err == nil {
+			err = starter.Start(ctx)
+		}
+		if err != nil {
+			errs := []error{fmt.Errorf("start %v: %w", typeKeys[i], err)}
+			for j := len(started) - 1; j >= 0; j-- {
+				if err := stop(ctx, typeKeys[started[j]], instances[started[j]]); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		}
+		started = append(started, i)
+	}
+	return nil
+}
+
+// Stop calls Stop(ctx) on the instantiated services implementing Stopper in
+// ShutdownOrder, the reverse of the order Start starts them in. Errors are
+// collected and returned together, and Stop stops early if ctx is done
+func (sl *ServiceLocator) Stop(ctx context.Context) error {
+	typeKeys, instances := sl.lifecycleOrder()
+
+	var errs []error
+	for i, instance := range instances {
+		if _, ok := instance.(Stopper); !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := stop(ctx, typeKeys[i], instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stop calls Stop on instance if it implements Stopper
+func stop(ctx context.Context, typeKey, instance any) error {
+	stopper, ok := instance.(Stopper)
+	if !ok {
+		return nil
+	}
+	if err := stopper.Stop(ctx); err != nil {
+		return fmt.Errorf("stop %v: %w", typeKey, err)
+	}
+	return nil
+}
+
+// lifecycleOrder returns the keys and instances held by the locator in
+// ShutdownOrder, with an instance registered under several types listed once
+func (sl *ServiceLocator) lifecycleOrder() ([]any, []any) {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	var typeKeys, instances []any
+	seen := make(map[any]bool)
+	for _, typeKey := range sl.shutdownOrder() {
+		instance := sl.instances[typeKey]
+		if instance == nil || !distinct(seen, instance) {
+			continue
+		}
+		typeKeys = append(typeKeys, typeKey)
+		instances = append(instances, instance)
+	}
+	return typeKeys, instances
+}
@@ -0,0 +1,133 @@
+package locator_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// component records its Start and Stop calls in a shared log
+type component struct {
+	name     string
+	log      *[]string
+	startErr error
+}
+
+func (c *component) Start(ctx context.Context) error {
+	*c.log = append(*c.log, "start "+c.name)
+	return c.startErr
+}
+
+func (c *component) Stop(ctx context.Context) error {
+	*c.log = append(*c.log, "stop "+c.name)
+	return nil
+}
+
+type Broker struct{ *component }
+
+type Consumer struct{ *component }
+
+type Publisher struct{ *component }
+
+// newLifecycleLocator registers a Consumer depending on a Broker registered after
+// it, and a Publisher that is never built
+func newLifecycleLocator(log *[]string, consumerErr error) *locator.ServiceLocator {
+	sl := locator.New()
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *Consumer {
+		locator.Get[*Broker](sl)
+		return &Consumer{&component{name: "consumer", log: log, startErr: consumerErr}}
+	})
+	locator.RegisterLazySingleton(sl, func() *Broker {
+		return &Broker{&component{name: "broker", log: log}}
+	})
+	locator.RegisterLazySingleton(sl, func() *Publisher {
+		return &Publisher{&component{name: "publisher", log: log}}
+	})
+	locator.Get[*Consumer](sl)
+	return sl
+}
+
+// Test Start starts services after their dependencies and Stop stops them in reverse
+func TestStartStop(t *testing.T) {
+	var log []string
+	sl := newLifecycleLocator(&log, nil)
+
+	if err := sl.Start(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := sl.Stop(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"start broker", "start consumer", "stop consumer", "stop broker"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+}
+
+// Test a failed start stops the services already started
+func TestStartFailure(t *testing.T) {
+	var log []string
+	sl := newLifecycleLocator(&log, errors.New("no connection"))
+
+	err := sl.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start *locator_test.Consumer: no connection") {
+		t.Fatalf("expected the start error, got %v", err)
+	}
+	expected := []string{"start broker", "start consumer", "stop broker"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+}
+
+// Test Start does nothing once ctx is done
+func TestStartCanceled(t *testing.T) {
+	var log []string
+	sl := newLifecycleLocator(&log, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sl.Start(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(log) != 0 {
+		t.Fatalf("expected nothing started, got %v", log)
+	}
+}
+
+// Table is a comparable service whose interface field holds an unhashable map
+type Table struct {
+	Rows any
+	log  *[]string
+}
+
+func (t Table) Start(ctx context.Context) error {
+	*t.log = append(*t.log, "start table")
+	return nil
+}
+
+func (t Table) Stop(ctx context.Context) error {
+	*t.log = append(*t.log, "stop table")
+	return nil
+}
+
+// Test Start and Stop handle services that cannot be used as map keys
+func TestStartStopUnhashable(t *testing.T) {
+	var log []string
+	sl := locator.New()
+	locator.RegisterSingleton(sl, Table{Rows: map[string]int{"a": 1}, log: &log})
+
+	if err := sl.Start(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := sl.Stop(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"start table", "stop table"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+}
@@ -1,106 +1,1420 @@
 package locator
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrFrozen is reported when registering on a locator after Freeze
+var ErrFrozen = errors.New("locator is frozen")
+
+// ErrDuplicateRegistration is reported when a type is registered again on a
+// locator created with WithStrictOverwrite
+var ErrDuplicateRegistration = errors.New("duplicate registration")
+
+// ErrCircularDependency is reported when resolving a type requires resolving it
+// again, directly or through other types
+var ErrCircularDependency = errors.New("circular dependency")
+
+// ErrLocatorMismatch is reported under WithLocatorCheck when a provider resolves
+// from another locator than the one it was invoked for
+var ErrLocatorMismatch = errors.New("provider resolved from a mismatched locator")
+
+// ErrForeignScope is reported under WithScopeGuard when a scoped type is resolved
+// from another scope than the one building a scoped instance
+var ErrForeignScope = errors.New("scoped type resolved from a foreign scope")
+
 // Provider is a function type that creates instances of services
 type Provider[T any] func() T
 
+// ProviderE is a function type that creates instances of services and may fail
+type ProviderE[T any] func() (T, error)
+
+// ProviderCtx is a function type that creates instances of services using the
+// context passed to GetCtx and may fail
+type ProviderCtx[T any] func(ctx context.Context) (T, error)
+
+// LocatorProvider is a function type that creates instances of services using
+// the locator they are resolved from, so it can resolve its own dependencies
+type LocatorProvider[T any] func(sl *ServiceLocator) T
+
+// LocatorProviderE is a function type that creates instances of services using
+// the locator they are resolved from and may fail, typically because one of its
+// dependencies cannot be resolved
+type LocatorProviderE[T any] func(sl *ServiceLocator) (T, error)
+
 // ServiceLocator manages service registration and retrieval
 type ServiceLocator struct {
-	mu        sync.RWMutex
-	instances map[any]any
-	providers map[any]any
+	*registry
+	// frame is the chain of types being built when this locator was handed to a
+	// provider, innermost first. Resolutions made through it are recorded as
+	// dependencies of the innermost type and checked for cycles
+	frame *resolutionFrame
+	// trace is the node of the resolution that received this locator when tracing
+	trace *traceNode
 }
 
-// New creates a new ServiceLocator instance
-func New() *ServiceLocator {
-	return &ServiceLocator{
-		instances: make(map[any]any),
-		providers: make(map[any]any),
+// registry holds the registrations shared by a locator and the views of it that
+// are handed to locator-aware providers
+type registry struct {
+	// mu serializes changes to the registrations. It is never held while a
+	// provider runs: each lazy singleton has its own lock, so a slow construction
+	// only holds up resolutions of its own type
+	mu           sync.RWMutex
+	instances    map[any]any
+	providers    map[any]any
+	decorators   map[any]any
+	groups       map[any]any
+	dependencies map[any][]reflect.Type
+	edges        map[any]map[any]struct{}
+	resolutions  sync.Map // type key -> *stripedCounter
+	frozen       bool
+	opts         options
+	// parent is the locator a scope was created from, nil for a root locator
+	parent *ServiceLocator
+	// scoped lists the scoped types instantiated in this scope in creation order
+	scoped []any
+	// constructed lists the keys of instances in the order they were stored,
+	// possibly with stale or repeated entries
+	constructed []any
+	// sites holds the call site of the latest registration of each key
+	sites  map[any]string
+	closed bool
+	// onResolve and onRegister hold the callbacks installed with OnResolve and
+	// OnRegister
+	onResolve  []ResolveHook
+	onRegister []RegisterHook
+	// events queues the events for the callbacks installed with Subscribe
+	events eventQueue
+	// snapshot is the copy of the registrations Get reads without locking, nil
+	// after a change until the next resolution
+	snapshot atomic.Pointer[snapshot]
+}
+
+// New creates a new ServiceLocator instance configured by opts
+func New(opts ...Option) *ServiceLocator {
+	sl := &ServiceLocator{registry: &registry{
+		instances:    make(map[any]any),
+		providers:    make(map[any]any),
+		decorators:   make(map[any]any),
+		groups:       make(map[any]any),
+		dependencies: make(map[any][]reflect.Type),
+		edges:        make(map[any]map[any]struct{}),
+		sites:        make(map[any]string),
+	}}
+	for _, opt := range opts {
+		opt(&sl.opts)
 	}
+	return sl
 }
 
-// RegisterSingleton registers an already created instance as a singleton
-func RegisterSingleton[T any](sl *ServiceLocator, instance T) {
+// CloneOption configures ServiceLocator.Clone
+type CloneOption func(*cloneOptions)
+
+// cloneOptions holds the configuration of ServiceLocator.Clone
+type cloneOptions struct {
+	shareSingletons bool
+}
+
+// ShareSingletons makes Clone share the lazy singletons the original has already
+// built instead of leaving them unmaterialized in the clone. Lazy singletons not
+// built yet are still constructed separately by each locator
+func ShareSingletons() CloneOption {
+	return func(o *cloneOptions) {
+		o.shareSingletons = true
+	}
+}
+
+// Clone creates a new ServiceLocator with the same registrations. The maps are
+// independent, so registering on the clone does not affect the original, and the
+// clone is never frozen.
+// Eager singletons are shared with the original, while lazy singletons start
+// unmaterialized in the clone so each locator constructs its own instance, unless
+// ShareSingletons is given
+func (sl *ServiceLocator) Clone(opts ...CloneOption) *ServiceLocator {
+	var o cloneOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	clone := New()
+	clone.opts = sl.opts
+	clone.parent = sl.parent
+	for typeKey, provider := range sl.providers {
+		// A cloned scope creates its own instances of scoped types
+		if r, ok := provider.(resolver); ok && r.kind() == KindScoped {
+			if _, ok := provider.(scopeFactory); !ok {
+				continue
+			}
+		}
+		if r, ok := provider.(resettable); ok {
+			provider = r.fresh()
+		}
+		clone.providers[typeKey] = provider
+	}
+	for _, typeKey := range sl.constructed {
+		// Instances that still have a provider are materialized lazy singletons
+		if provider, exists := sl.providers[typeKey]; exists {
+			if r, ok := provider.(resolver); !o.shareSingletons || !ok || r.kind() != KindLazySingleton {
+				continue
+			}
+		}
+		if instance, exists := sl.instances[typeKey]; exists {
+			clone.storeInstance(typeKey, instance)
+		}
+	}
+	for typeKey, decorators := range sl.decorators {
+		clone.decorators[typeKey] = decorators
+	}
+	for groupKey, members := range sl.groups {
+		clone.groups[groupKey] = members
+	}
+	clone.freshGroups(sl.groups, o.shareSingletons)
+	for typeKey, deps := range sl.dependencies {
+		clone.dependencies[typeKey] = deps
+	}
+	for typeKey, site := range sl.sites {
+		clone.sites[typeKey] = site
+	}
+	clone.onResolve = sl.onResolve
+	clone.onRegister = sl.onRegister
+	for typeKey, deps := range sl.edges {
+		clone.edges[typeKey] = make(map[any]struct{}, len(deps))
+		for dep := range deps {
+			clone.edges[typeKey][dep] = struct{}{}
+		}
+	}
+	return clone
+}
+
+// Freeze makes the locator read-only. Any later registration panics with an error
+// wrapping ErrFrozen, or returns it for registration functions that report errors.
+// Resolution keeps working, including the construction and caching of lazy
+// singletons that were registered before freezing
+func (sl *ServiceLocator) Freeze() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.frozen = true
+}
+
+// Reset drops every registration at once, returning the locator to the state New
+// left it in while keeping its options. Providers, instances, decorators, groups,
+// declared dependencies and statistics are all discarded
+func (sl *ServiceLocator) Reset() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if sl.frozen {
+		panic(fmt.Errorf("%w: cannot reset", ErrFrozen))
+	}
+	for typeKey := range sl.providers {
+		sl.emit(EventUnregister, typeKey, KindUnregistered, "", 0)
+	}
+	for typeKey := range sl.instances {
+		if _, exists := sl.providers[typeKey]; !exists {
+			sl.emit(EventUnregister, typeKey, KindUnregistered, "", 0)
+		}
+	}
+
+	sl.instances = make(map[any]any)
+	sl.providers = make(map[any]any)
+	sl.decorators = make(map[any]any)
+	sl.changed()
+	sl.groups = make(map[any]any)
+	sl.dependencies = make(map[any][]reflect.Type)
+	sl.edges = make(map[any]map[any]struct{})
+	sl.sites = make(map[any]string)
+	sl.constructed = nil
+	sl.scoped = nil
+	sl.resolutions.Range(func(typeKey, _ any) bool {
+		sl.resolutions.Delete(typeKey)
+		return true
+	})
+}
+
+// ResetInstances discards every instance cached by a provider so that lazy
+// singletons, including tagged ones, and cached factories are rebuilt on the next
+// Get. Provider
+// registrations are kept, as are singletons registered with RegisterSingleton
+// since they have no provider to rebuild them from
+func (sl *ServiceLocator) ResetInstances() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	for typeKey, provider := range sl.providers {
+		delete(sl.instances, typeKey)
+		if r, ok := provider.(resettable); ok {
+			sl.providers[typeKey] = r.fresh()
+		}
+	}
+	sl.freshGroups(sl.groups, false)
+	sl.changed()
+}
+
+// Unregister removes the registration of T, both its provider and any instance
+// held for it, so the next Get fails as if T had never been registered.
+// Decorators and declared dependencies of T are kept. It reports whether T was
+// registered
+func Unregister[T any](sl *ServiceLocator) bool {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	typeKey := getTypeKey[T]()
+	sl.mustNotBeFrozen(typeKey)
+	if !sl.isRegistered(typeKey) {
+		return false
+	}
+	delete(sl.providers, typeKey)
+	delete(sl.instances, typeKey)
+	delete(sl.sites, typeKey)
+	sl.changed()
+	sl.emit(EventUnregister, typeKey, KindUnregistered, "", 0)
+	return true
+}
+
+// Override replaces the registration of T with instance, typically a test double,
+// and returns a function restoring the previous registration, including an
+// instance a lazy singleton had already built. Unlike RegisterSingleton it does
+// not apply decorators, so Get returns instance itself. Calling restore more than
+// once has no further effect
+func Override[T any](sl *ServiceLocator, instance T) (restore func()) {
+	typeKey := getTypeKey[T]()
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(typeKey)
+	provider, hadProvider := sl.providers[typeKey]
+	previous, hadInstance := sl.instances[typeKey]
+	site, hadSite := sl.sites[typeKey]
+	// Overriding is deliberate, so it is allowed under WithStrictOverwrite
+	if err := sl.accept(typeKey, KindSingleton, sl.isRegistered(typeKey), callerSite()); err != nil {
+		panic(err)
+	}
+	delete(sl.providers, typeKey)
+	sl.storeInstance(typeKey, instance)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			sl.mu.Lock()
+			defer sl.mu.Unlock()
+			delete(sl.providers, typeKey)
+			delete(sl.instances, typeKey)
+			delete(sl.sites, typeKey)
+			if hadProvider {
+				sl.providers[typeKey] = provider
+			}
+			if hadInstance {
+				sl.storeInstance(typeKey, previous)
+			}
+			if hadSite {
+				sl.sites[typeKey] = site
+			}
+			sl.changed()
+			if r, ok := provider.(resolver); ok {
+				sl.emit(EventOverwrite, typeKey, r.kind(), site, 0)
+			} else if hadInstance {
+				sl.emit(EventOverwrite, typeKey, KindSingleton, site, 0)
+			} else {
+				sl.emit(EventUnregister, typeKey, KindUnregistered, "", 0)
+			}
+		})
+	}
+}
+
+// Replace atomically swaps the singleton of T for instance and returns the
+// previous one, so a configuration or feature-flag client can be reloaded while
+// the locator is in use. Concurrent Gets return either the previous or the new
+// instance, never a mix. instance is decorated like RegisterSingleton before the
+// swap. A lazy singleton that was not built yet is replaced without building it,
+// and previous is then the zero value. Replace is allowed on a frozen locator
+// since it swaps an existing singleton rather than registering one. It fails if T
+// is not registered or is not a singleton
+func Replace[T any](sl *ServiceLocator, instance T) (previous T, err error) {
+	typeKey := getTypeKey[T]()
+	instance = decorate(sl, instance)
+
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
-	sl.instances[getTypeKey[T]()] = instance
+	if !sl.isRegistered(typeKey) {
+		return previous, notRegistered(typeKey)
+	}
+	if provider, ok := sl.providers[typeKey].(resolver); ok && provider.kind() != KindLazySingleton {
+		return previous, fmt.Errorf("cannot replace %v registered as %v", typeKey, provider.kind())
+	}
+	previous = castInstance[T](sl.instances[typeKey])
+	// Dropping the provider also keeps a construction in flight from promoting
+	// its stale instance over the replacement
+	delete(sl.providers, typeKey)
+	sl.storeInstance(typeKey, instance)
+	return previous, nil
+}
+
+// EstimateSize returns a rough estimate of the memory held by each materialized
+// singleton. The estimate is shallow: it is the size of the stored value itself
+// (a pointer counts as one word) and does not follow pointers, slices or maps
+func EstimateSize(sl *ServiceLocator) map[reflect.Type]uintptr {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	sizes := make(map[reflect.Type]uintptr, len(sl.instances))
+	for typeKey := range sl.instances {
+		typ, ok := typeKey.(reflect.Type)
+		if !ok {
+			continue
+		}
+		sizes[typ] = typ.Size()
+	}
+	return sizes
+}
+
+// RegisterSingleton registers an already created instance as a singleton
+func RegisterSingleton[T any](sl *ServiceLocator, instance T) {
+	registerInstance(sl, instance, false)
+}
+
+// RegisterValue registers value as a singleton. It is equivalent to
+// RegisterSingleton and exists to make intent clear for non-pointer values such as
+// configuration structs, slices and maps. Composite types are keyed by their full
+// type, so []string, []int and map[string]int are all distinct registrations
+func RegisterValue[T any](sl *ServiceLocator, value T) {
+	registerInstance(sl, value, false)
+}
+
+// RegisterAs registers instance as the singleton of the interface I, so that
+// Get[I] resolves it. It panics if T does not implement I
+func RegisterAs[I, T any](sl *ServiceLocator, instance T) {
+	bound, ok := any(instance).(I)
+	if !ok {
+		panic(fmt.Errorf("type %v does not implement %v", getTypeKey[T](), getTypeKey[I]()))
+	}
+	registerInstance(sl, bound, false)
 }
 
 // RegisterLazySingleton registers a provider function that will be used to create
 // a singleton instance on first access
 func RegisterLazySingleton[T any](sl *ServiceLocator, provider Provider[T]) {
-	sl.mu.Lock()
-	defer sl.mu.Unlock()
-	sl.providers[getTypeKey[T]()] = &lazySingleton[T]{
-		provider: provider,
+	if !acceptProvider[T](sl, provider == nil) {
+		return
 	}
+	registerProvider[T](sl, &lazySingleton[T]{provider: provider.withLocator().withError()}, false)
+}
+
+// RegisterLazySingletonWithLocator registers a provider function that receives the
+// owning locator and will be used to create a singleton instance on first access
+func RegisterLazySingletonWithLocator[T any](sl *ServiceLocator, provider LocatorProvider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, &lazySingleton[T]{provider: provider.withError()}, false)
 }
 
 // RegisterFactory registers a provider function that will create a new instance
 // each time Get is called
 func RegisterFactory[T any](sl *ServiceLocator, provider Provider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, provider, false)
+}
+
+// RegisterFactoryWithLocator registers a provider function that receives the owning
+// locator and will create a new instance each time Get is called
+func RegisterFactoryWithLocator[T any](sl *ServiceLocator, provider LocatorProvider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, provider, false)
+}
+
+// RegisterFactoryE registers a provider function that may fail and will create a
+// new instance each time Get is called. The provider's error is returned from Get
+func RegisterFactoryE[T any](sl *ServiceLocator, provider ProviderE[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, provider, false)
+}
+
+// RegisterFactoryCtx registers a provider function that receives the context
+// passed to GetCtx, so it can honor deadlines and read request scoped values, and
+// will create a new instance each time the type is resolved. Get passes
+// context.Background()
+func RegisterFactoryCtx[T any](sl *ServiceLocator, provider ProviderCtx[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, provider, false)
+}
+
+// RegisterLazySingletonE registers a provider function that may fail and will be
+// used to create a singleton instance on first access. A failed construction is
+// returned from Get and retried on the next one
+func RegisterLazySingletonE[T any](sl *ServiceLocator, provider ProviderE[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, &lazySingleton[T]{provider: provider.withLocator()}, false)
+}
+
+// RegisterLazySingletonWith registers a provider function that receives the
+// owning locator to resolve its dependencies and may fail. It will be used to
+// create a singleton instance on first access; a failed construction is returned
+// from Get and retried on the next one
+func RegisterLazySingletonWith[T any](sl *ServiceLocator, provider LocatorProviderE[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, &lazySingleton[T]{provider: provider}, false)
+}
+
+// RegisterFactoryWith registers a provider function that receives the owning
+// locator to resolve its dependencies and may fail. It will create a new instance
+// each time Get is called, and its error is returned from Get
+func RegisterFactoryWith[T any](sl *ServiceLocator, provider LocatorProviderE[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, provider, false)
+}
+
+// RegisterSingletonWithMigrate registers instance as a singleton like
+// RegisterSingleton, but when an instance of T already exists it stores the result
+// of migrate(old, instance) instead, allowing state to be carried over during a
+// hot swap. Only materialized instances are migrated; a provider that has not
+// built its instance yet is simply replaced. The registration is checked before
+// migrate runs, so a rejected one has no side effects
+func RegisterSingletonWithMigrate[T any](sl *ServiceLocator, instance T, migrate func(old, new T) T) {
+	typeKey := getTypeKey[T]()
+
+	sl.mu.Lock()
+	err := sl.frozenError(typeKey)
+	if err == nil {
+		err = sl.admit(typeKey, KindSingleton, sl.isRegistered(typeKey))
+	}
+	old, exists := sl.instances[typeKey]
+	sl.mu.Unlock()
+	if err != nil {
+		panic(err)
+	}
+
+	// migrate and the decorators run without holding the lock so they can resolve
+	// from the locator
+	if exists {
+		instance = migrate(castInstance[T](old), instance)
+	}
+	instance = decorate(sl, instance)
+
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
-	sl.providers[getTypeKey[T]()] = provider
+	// The registration was admitted above, only a Freeze since can reject it
+	sl.mustNotBeFrozen(typeKey)
+	delete(sl.providers, typeKey)
+	sl.storeInstance(typeKey, instance)
+}
+
+// RegisterDefaultSingleton registers instance as a singleton only if nothing is
+// registered for T yet, letting libraries install defaults that the application
+// may have already overridden. It reports whether the default was installed
+func RegisterDefaultSingleton[T any](sl *ServiceLocator, instance T) bool {
+	return registerInstance(sl, instance, true)
+}
+
+// RegisterDefaultLazySingleton registers provider as a lazy singleton only if
+// nothing is registered for T yet. It reports whether the default was installed
+func RegisterDefaultLazySingleton[T any](sl *ServiceLocator, provider Provider[T]) bool {
+	if !acceptProvider[T](sl, provider == nil) {
+		return false
+	}
+	return registerProvider[T](sl, &lazySingleton[T]{provider: provider.withLocator().withError()}, true)
+}
+
+// RegisterDefaultFactory registers provider as a factory only if nothing is
+// registered for T yet. It reports whether the default was installed
+func RegisterDefaultFactory[T any](sl *ServiceLocator, provider Provider[T]) bool {
+	if !acceptProvider[T](sl, provider == nil) {
+		return false
+	}
+	return registerProvider[T](sl, provider, true)
+}
+
+// RegisterByMetadata registers a set of instances keyed by a string derived from
+// the resolution context. GetCtx calls keyFn with its context to pick the instance,
+// which allows per-tenant or per-region selection driven by request metadata. A nil
+// keyFn is rejected like a nil provider
+func RegisterByMetadata[T any](sl *ServiceLocator, keyFn func(context.Context) string, instances map[string]T) {
+	if !acceptProvider[T](sl, keyFn == nil) {
+		return
+	}
+	copied := make(map[string]T, len(instances))
+	for key, instance := range instances {
+		copied[key] = instance
+	}
+
+	registerProvider[T](sl, &metadataProvider[T]{
+		keyFn:     keyFn,
+		instances: copied,
+	}, false)
+}
+
+// acceptProvider reports whether a provider should be stored. A nil provider is
+// skipped, leaving T unregistered, or panics with WithStrictRegistration
+func acceptProvider[T any](sl *ServiceLocator, isNil bool) bool {
+	if !isNil {
+		return true
+	}
+	if sl.opts.strictRegistration {
+		panic(fmt.Errorf("nil provider for type %v", getTypeKey[T]()))
+	}
+	return false
+}
+
+// registerInstance stores instance as the singleton for T, replacing any provider.
+// With ifAbsent set nothing is stored when T is already registered. It reports
+// whether instance was stored
+func registerInstance[T any](sl *ServiceLocator, instance T, ifAbsent bool) bool {
+	return registerInstanceAt(sl, getTypeKey[T](), instance, ifAbsent)
+}
+
+// registerInstanceAt stores instance under typeKey. It is the core of
+// registerInstance for keys that are not plain types
+func registerInstanceAt[T any](sl *ServiceLocator, typeKey any, instance T, ifAbsent bool) bool {
+	if ifAbsent && sl.hasRegistration(typeKey) {
+		return false
+	}
+
+	// Decorators run without holding the lock so they can resolve from the locator
+	instance = decorate(sl, instance)
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(typeKey)
+	if ifAbsent && sl.isRegistered(typeKey) {
+		return false
+	}
+	sl.mustAdmit(typeKey, KindSingleton, sl.isRegistered(typeKey))
+	delete(sl.providers, typeKey)
+	sl.storeInstance(typeKey, instance)
+	return true
+}
+
+// registerProvider stores provider for T, dropping any cached instance. With
+// ifAbsent set nothing is stored when T is already registered. It reports whether
+// provider was stored
+func registerProvider[T any](sl *ServiceLocator, provider resolver, ifAbsent bool) bool {
+	return sl.registerResolver(getTypeKey[T](), provider, ifAbsent)
+}
+
+// registerResolver stores provider under typeKey, dropping any cached instance.
+// It is the untyped core of registerProvider for keys that are not plain types
+func (sl *ServiceLocator) registerResolver(typeKey any, provider resolver, ifAbsent bool) bool {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.mustNotBeFrozen(typeKey)
+	if ifAbsent && sl.isRegistered(typeKey) {
+		return false
+	}
+	sl.mustAdmit(typeKey, provider.kind(), sl.isRegistered(typeKey))
+	delete(sl.instances, typeKey)
+	sl.providers[typeKey] = provider
+	sl.changed()
+	return true
+}
+
+// admit checks that registering typeKey with the given kind is allowed: under
+// WithStrictOverwrite an overwrite is rejected, and otherwise the registration
+// hooks decide. Unless rejected, the caller is recorded as the call site of
+// typeKey. The caller must hold sl.mu
+func (sl *ServiceLocator) admit(typeKey any, kind Kind, overwrite bool) error {
+	site := callerSite()
+	if overwrite && sl.opts.strictOverwrite {
+		previous := sl.sites[typeKey]
+		if previous == "" {
+			previous = "unknown location"
+		}
+		return fmt.Errorf("%w: %v registered at %s, again at %s", ErrDuplicateRegistration, typeKey, previous, site)
+	}
+	return sl.accept(typeKey, kind, overwrite, site)
+}
+
+// accept runs the registration hooks for registering typeKey from site and, unless
+// one of them rejects it, records site as the call site of typeKey. The caller
+// must hold sl.mu
+func (sl *ServiceLocator) accept(typeKey any, kind Kind, overwrite bool, site string) error {
+	if err := sl.announce(typeKey, kind, overwrite, site); err != nil {
+		return err
+	}
+	if site != "" {
+		sl.sites[typeKey] = site
+	}
+	return nil
+}
+
+// announce runs the registration hooks for registering typeKey from site and,
+// unless one of them rejects it, logs the registration and reports it to
+// subscribers. Group members are announced without being recorded as
+// registrations. The caller must hold sl.mu
+func (sl *ServiceLocator) announce(typeKey any, kind Kind, overwrite bool, site string) error {
+	ev := RegisterEvent{Type: keyType(typeKey), Kind: kind, CallSite: site, Overwrite: overwrite}
+	for _, hook := range sl.registerHooks() {
+		if err := hook(ev); err != nil {
+			return fmt.Errorf("registration of %v rejected: %w", typeKey, err)
+		}
+	}
+
+	if sl.opts.logger != nil {
+		sl.opts.logger.registered(typeKey, ev)
+	}
+	op := EventRegister
+	if overwrite {
+		op = EventOverwrite
+	}
+	sl.emit(op, typeKey, kind, site, 0)
+	return nil
+}
+
+// mustAdmit panics if admit rejects registering typeKey. The caller must hold sl.mu
+func (sl *ServiceLocator) mustAdmit(typeKey any, kind Kind, overwrite bool) {
+	if err := sl.admit(typeKey, kind, overwrite); err != nil {
+		panic(err)
+	}
+}
+
+// callerSite returns the file:line of the first caller outside this package, or
+// an empty string if there is none
+func callerSite() string {
+	if frame := externalCaller(); frame.File != "" {
+		return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	}
+	return ""
+}
+
+// storeInstance stores instance under typeKey and records when it was stored. The
+// caller must hold sl.mu
+func (sl *ServiceLocator) storeInstance(typeKey, instance any) {
+	sl.instances[typeKey] = instance
+	sl.constructed = append(sl.constructed, typeKey)
+	sl.changed()
+
+	// Drop stale entries once they dominate, so re-registration cannot grow the
+	// list without bound
+	if len(sl.constructed) > 2*len(sl.instances)+32 {
+		sl.constructed = sl.constructionOrder()
+	}
+}
+
+// constructionOrder returns the keys of the current instances, oldest first. An
+// instance stored several times counts from its last store. The caller must hold
+// sl.mu
+func (sl *ServiceLocator) constructionOrder() []any {
+	seen := make(map[any]bool, len(sl.instances))
+	order := make([]any, 0, len(sl.instances))
+	for i := len(sl.constructed) - 1; i >= 0; i-- {
+		typeKey := sl.constructed[i]
+		if _, exists := sl.instances[typeKey]; !exists || seen[typeKey] {
+			continue
+		}
+		seen[typeKey] = true
+		order = append(order, typeKey)
+	}
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// frozenError returns the error reported when registering typeKey on a frozen
+// locator, or nil if the locator is not frozen. The caller must hold sl.mu
+func (sl *ServiceLocator) frozenError(typeKey any) error {
+	if !sl.frozen {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot register %v", ErrFrozen, typeKey)
+}
+
+// mustNotBeFrozen panics if the locator is frozen. The caller must hold sl.mu
+func (sl *ServiceLocator) mustNotBeFrozen(typeKey any) {
+	if err := sl.frozenError(typeKey); err != nil {
+		panic(err)
+	}
+}
+
+// hasRegistration reports whether typeKey has an instance or a provider
+func (sl *ServiceLocator) hasRegistration(typeKey any) bool {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	return sl.isRegistered(typeKey)
+}
+
+// isRegistered reports whether typeKey has an instance or a provider. The caller
+// must hold sl.mu
+func (sl *ServiceLocator) isRegistered(typeKey any) bool {
+	if _, exists := sl.instances[typeKey]; exists {
+		return true
+	}
+	_, exists := sl.providers[typeKey]
+	return exists
 }
 
 // Get retrieves an instance of the requested type
 func Get[T any](sl *ServiceLocator) (T, error) {
-	sl.mu.RLock()
+	return GetCtx[T](context.Background(), sl)
+}
+
+// GetCtx retrieves an instance of the requested type, passing ctx to providers
+// that depend on the resolution context
+func GetCtx[T any](ctx context.Context, sl *ServiceLocator) (T, error) {
+	if instance, ok := sl.cached(getTypeKey[T]()); ok {
+		return castInstance[T](instance), nil
+	}
+	var zero T
 	typeKey := getTypeKey[T]()
+	instance, found, err := sl.resolve(ctx, typeKey)
+	if !found {
+		return zero, sl.missingError(typeKey)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return castInstance[T](instance), nil
+}
 
-	if instance, exists := sl.instances[typeKey]; exists {
-		sl.mu.RUnlock()
-		return instance.(T), nil
+// TryGet retrieves an instance of the requested type using the comma-ok idiom. It
+// reports false when the type is not registered or its provider fails, without
+// constructing an error for the missing case
+func TryGet[T any](sl *ServiceLocator) (T, bool) {
+	var zero T
+	instance, found, err := sl.resolve(context.Background(), getTypeKey[T]())
+	if !found || err != nil {
+		return zero, false
 	}
+	return castInstance[T](instance), true
+}
 
-	if provider, exists := sl.providers[typeKey]; exists {
-		sl.mu.RUnlock()
+// Has reports whether T is registered, including registrations a scope inherits,
+// without running any provider
+func Has[T any](sl *ServiceLocator) bool {
+	return sl.hasInherited(getTypeKey[T]())
+}
 
-		switch p := provider.(type) {
-		case *lazySingleton[T]:
-			return p.getInstance(sl)
-		case Provider[T]:
-			return p(), nil
+// hasInherited reports whether typeKey is registered on sl or one of its ancestors
+func (sl *ServiceLocator) hasInherited(typeKey any) bool {
+	for p := sl; p != nil; p = p.parent {
+		if p.hasRegistration(typeKey) {
+			return true
 		}
-	} else {
-		sl.mu.RUnlock()
 	}
+	return false
+}
 
-	var zero T
-	return zero, fmt.Errorf("no provider registered for type %T", zero)
+// inheritedProvider returns the provider of the nearest registration of typeKey
+// in sl or its ancestors, nil if that registration is an instance or there is
+// none. The caller must not hold sl.mu
+func (sl *ServiceLocator) inheritedProvider(typeKey any) any {
+	for p := sl; p != nil; p = p.parent {
+		view := p.view()
+		if provider, exists := view.providers[typeKey]; exists {
+			return provider
+		}
+		if view.has(typeKey) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// MustGet retrieves an instance of the requested type, panicking with the
+// resolution error if it fails. It is meant for wiring code where a missing
+// service is a programming error
+func MustGet[T any](sl *ServiceLocator) T {
+	instance, err := Get[T](sl)
+	if err != nil {
+		panic(fmt.Errorf("cannot resolve %v: %w", getTypeKey[T](), err))
+	}
+	return instance
+}
+
+// GetOr retrieves an instance of the requested type, returning fallback when the
+// type is not registered or its provider fails. A provider that returns the zero
+// value is a successful resolution and its result is returned as is
+func GetOr[T any](sl *ServiceLocator, fallback T) T {
+	instance, err := Get[T](sl)
+	if err != nil {
+		return fallback
+	}
+	return instance
+}
+
+// GetOrElse is like GetOr but only calls fallback when resolution fails, which
+// avoids building an unused default
+func GetOrElse[T any](sl *ServiceLocator, fallback func() T) T {
+	instance, err := Get[T](sl)
+	if err != nil {
+		return fallback()
+	}
+	return instance
+}
+
+// resolve looks up the registration for typeKey and produces an instance of it.
+// It is the untyped core shared by every resolution function. found reports
+// whether typeKey is registered at all, so callers can tell a missing registration
+// apart from a failing provider without an error being built
+func (sl *ServiceLocator) resolve(ctx context.Context, typeKey any) (instance any, found bool, err error) {
+	if sl.opts.locatorCheck != nil {
+		sl.checkLocator(typeKey)
+	}
+	// Hooks are called once every lock has been released, so they may use the locator
+	if hooks := sl.resolveHooks(); len(hooks) > 0 {
+		start := time.Now()
+		defer func() {
+			hookErr := err
+			if !found {
+				hookErr = sl.missingError(typeKey)
+			}
+			for _, hook := range hooks {
+				hook(keyType(typeKey), instance, time.Since(start), hookErr)
+			}
+		}()
+	}
+	if logger := sl.opts.logger; logger != nil {
+		defer func() {
+			if !found {
+				logger.failed(typeKey, sl.missingError(typeKey))
+			} else if err != nil {
+				logger.failed(typeKey, err)
+			}
+		}()
+	}
+	if sl.opts.tracer != nil {
+		return sl.opts.tracer.resolve(ctx, sl, typeKey)
+	}
+	return sl.resolveRegistration(ctx, typeKey)
+}
+
+// resolveRegistration is resolve without the resolution hooks. A scope delegates
+// to its parent through it, so hooks run once for the locator resolved from
+func (sl *ServiceLocator) resolveRegistration(ctx context.Context, typeKey any) (instance any, found bool, err error) {
+	if sl.frame != nil {
+		sl.recordEdge(sl.frame.typeKey, typeKey)
+		if path := sl.frame.cycle(typeKey); path != nil {
+			return nil, true, cycleError(path, sl.callSite)
+		}
+	}
+
+	// A scope resolves inherited registrations through its parent, except scoped
+	// types which it instantiates itself
+	var scoped resolver
+	if sl.parent != nil && !sl.view().has(typeKey) {
+		var ok bool
+		if scoped, ok = sl.inheritedScoped(typeKey); !ok {
+			parent := sl.parent
+			if parent.frame != sl.frame || parent.trace != sl.trace {
+				parent = &ServiceLocator{registry: parent.registry, frame: sl.frame, trace: sl.trace}
+			}
+			return parent.resolveRegistration(ctx, typeKey)
+		}
+	}
+	sl.countResolution(typeKey)
+
+	var ev ResolveEvent
+	// The observer is called once every lock has been released, so it may use the locator
+	if sl.opts.observer != nil {
+		defer func() {
+			ev.Type = fmt.Sprint(typeKey)
+			ev.Err = err
+			if !found {
+				ev.Err = sl.missingError(typeKey)
+			}
+			sl.opts.observer(ev)
+		}()
+	}
+	if sl.trace != nil {
+		defer func() {
+			sl.trace.kind, sl.trace.built = ev.Kind, !ev.CacheHit
+		}()
+	}
+
+	view := sl.view()
+	if cached, exists := view.instances[typeKey]; exists {
+		ev.Kind, ev.CacheHit = cached.kind, true
+		if cached.kind == KindScoped && sl.opts.scopeGuard {
+			done, err := sl.guardScope(typeKey)
+			if err != nil {
+				return nil, true, err
+			}
+			done()
+		}
+		return cached.instance, true, nil
+	}
+	provider, hasProvider := view.providers[typeKey]
+
+	r, ok := provider.(resolver)
+	if !hasProvider || !ok {
+		if r, ok = scoped, scoped != nil; !ok {
+			if r, ok = sl.fallbackResolver(typeKey); !ok {
+				return nil, false, nil
+			}
+		}
+	}
+
+	ev.Kind = r.kind()
+	if ev.Kind == KindScoped && sl.opts.scopeGuard {
+		done, err := sl.guardScope(typeKey)
+		if err != nil {
+			return nil, true, err
+		}
+		defer done()
+	}
+	start := time.Now()
+	instance, built, err := sl.runResolver(ctx, typeKey, r)
+	ev.CacheHit = !built
+	if built {
+		ev.Duration = time.Since(start)
+		if err == nil && (ev.Kind == KindLazySingleton || ev.Kind == KindScoped) {
+			if logger := sl.opts.logger; logger != nil {
+				logger.constructed(typeKey, ev.Kind, ev.Duration)
+			}
+			sl.emit(EventInstantiate, typeKey, ev.Kind, "", ev.Duration)
+		}
+	}
+	return instance, true, err
+}
+
+// fallbackResolver returns a resolver for a type that has no registration, if the
+// locator is configured to synthesize one. The caller must not hold sl.mu
+func (sl *ServiceLocator) fallbackResolver(typeKey any) (resolver, bool) {
+	typ, ok := typeKey.(reflect.Type)
+	if !ok {
+		return nil, false
+	}
+	if sl.opts.pointerValueFallback {
+		if source, ok := sl.pointerValueCounterpart(typ); ok {
+			return pointerValueResolver{typ: typ, source: source}, true
+		}
+	}
+	if sl.opts.autoStruct && typ.Kind() == reflect.Pointer && typ.Elem().Kind() == reflect.Struct {
+		return structConstructor{typ: typ}, true
+	}
+	return nil, false
+}
+
+// notRegistered returns the error reported when typeKey has no registration
+func notRegistered(typeKey any) error {
+	return fmt.Errorf("no provider registered for type %v", typeKey)
+}
+
+// missingError returns the error reported when resolving typeKey finds no
+// registration. It hints at a registered value or pointer counterpart, the usual
+// cause of the mismatch. The caller must not hold sl.mu
+func (sl *ServiceLocator) missingError(typeKey any) error {
+	if typ, ok := typeKey.(reflect.Type); ok {
+		if source, ok := sl.pointerValueCounterpart(typ); ok {
+			return fmt.Errorf("no provider registered for type %v (%v is registered; use WithPointerValueFallback to resolve it)", typ, source)
+		}
+	}
+	return notRegistered(typeKey)
+}
+
+// castInstance converts a resolved instance to T, mapping nil to the zero value
+// so that nil interface registrations do not panic
+func castInstance[T any](instance any) T {
+	typed, _ := instance.(T)
+	return typed
+}
+
+// resolver is implemented by every provider kind stored in the providers map
+type resolver interface {
+	// resolve produces an instance, reporting whether a provider was run to build
+	// it rather than a cached instance being returned
+	resolve(ctx context.Context, sl *ServiceLocator) (instance any, built bool, err error)
+	// kind reports the lifetime of the registration
+	kind() Kind
+}
+
+// resolve creates a new decorated instance
+func (p Provider[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	return decorate(sl, p()), true, nil
+}
+
+// kind reports that p is a factory
+func (p Provider[T]) kind() Kind {
+	return KindFactory
+}
+
+// resolve creates a new decorated instance using sl
+func (p LocatorProvider[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	return decorate(sl, p(sl.resolving(getTypeKey[T]()))), true, nil
+}
+
+// kind reports that p is a factory
+func (p LocatorProvider[T]) kind() Kind {
+	return KindFactory
+}
+
+// resolve builds a new instance using sl, returning the provider's error if it fails
+func (p LocatorProviderE[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	instance, err := p(sl.resolving(getTypeKey[T]()))
+	if err != nil {
+		return nil, true, err
+	}
+	return decorate(sl, instance), true, nil
+}
+
+// kind reports that p is a factory
+func (p LocatorProviderE[T]) kind() Kind {
+	return KindFactory
+}
+
+// GetNonNil retrieves an instance of the requested type, substituting def when the
+// registered value is a nil pointer, interface, map, slice, channel or function.
+// Resolution errors are returned unchanged
+func GetNonNil[T any](sl *ServiceLocator, def T) (T, error) {
+	instance, err := Get[T](sl)
+	if err != nil {
+		return instance, err
+	}
+	if isNil(instance) {
+		return def, nil
+	}
+	return instance, nil
+}
+
+// GetChecked retrieves an instance of the requested type and verifies that it
+// implements Iface, which catches a registered type drifting away from a contract
+// it is expected to satisfy
+func GetChecked[T, Iface any](sl *ServiceLocator) (T, error) {
+	instance, err := Get[T](sl)
+	if err != nil {
+		return instance, err
+	}
+	if _, ok := any(instance).(Iface); !ok {
+		var zero T
+		return zero, fmt.Errorf("type %T does not implement %v", instance, getTypeKey[Iface]())
+	}
+	return instance, nil
+}
+
+// isNil reports whether v is nil or holds a nil value of a nillable kind
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// withLocator adapts p to a LocatorProvider, keeping a nil provider nil
+func (p Provider[T]) withLocator() LocatorProvider[T] {
+	if p == nil {
+		return nil
+	}
+	return func(*ServiceLocator) T {
+		return p()
+	}
+}
+
+// withError adapts p to a provider that may fail, keeping a nil provider nil
+func (p LocatorProvider[T]) withError() func(*ServiceLocator) (T, error) {
+	if p == nil {
+		return nil
+	}
+	return func(sl *ServiceLocator) (T, error) {
+		return p(sl), nil
+	}
+}
+
+// withLocator adapts p to a provider receiving the locator, keeping a nil
+// provider nil
+func (p ProviderE[T]) withLocator() func(*ServiceLocator) (T, error) {
+	if p == nil {
+		return nil
+	}
+	return func(*ServiceLocator) (T, error) {
+		return p()
+	}
+}
+
+// resolve builds a new instance, returning the provider's error if it fails
+func (p ProviderE[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	instance, err := p()
+	if err != nil {
+		return nil, true, err
+	}
+	return decorate(sl, instance), true, nil
+}
+
+// kind reports that p builds a new instance on every resolution
+func (p ProviderE[T]) kind() Kind {
+	return KindFactory
+}
+
+// resolve builds a new instance with ctx, returning the provider's error if it fails
+func (p ProviderCtx[T]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	instance, err := p(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	return decorate(sl, instance), true, nil
 }
 
-// lazySingleton wraps a provider function and ensures only one instance is created
+// kind reports that p builds a new instance on every resolution
+func (p ProviderCtx[T]) kind() Kind {
+	return KindFactory
+}
+
+// resettable is implemented by providers that cache state which must not be
+// shared when the registration is copied
+type resettable interface {
+	fresh() any
+}
+
+// lazySingleton wraps a provider function and ensures only one instance is created.
+// done is only set once the provider succeeds, so a provider that panics can be
+// retried by a later Get. Concurrent callers share the construction in flight
 type lazySingleton[T any] struct {
-	once     sync.Once
+	mu       sync.Mutex
+	done     bool
+	instance T
+	provider func(*ServiceLocator) (T, error)
+	info     *SingletonInfo
+	flight   *flight[T]
+	// key is the registration key when it is not the type of T, as for named
+	// registrations
+	key any
+	// scoped marks the instance of a scoped type owned by a scope
+	scoped bool
+	// failure is the error of the latest construction, returned until retryAt
+	// under WithLazyRetry, and failures counts consecutive failed constructions
+	failure  error
+	failures int
+	retryAt  time.Time
+	forever  bool
+	// duration is how long the provider took to build instance, in nanoseconds
+	duration atomic.Int64
+}
+
+// flight is a single construction of a lazy singleton. done is closed once
+// instance and err are set
+type flight[T any] struct {
+	done     chan struct{}
 	instance T
-	provider Provider[T]
+	err      error
+	// goroutine is the id of the goroutine running the provider, 0 until it starts
+	goroutine atomic.Uint64
+}
+
+// fresh returns an unmaterialized copy of the lazy singleton
+func (ls *lazySingleton[T]) fresh() any {
+	return &lazySingleton[T]{provider: ls.provider, key: ls.key, scoped: ls.scoped}
+}
+
+// resolve returns the singleton instance, creating it if necessary
+func (ls *lazySingleton[T]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	return ls.getInstance(ctx, sl)
+}
+
+// kind reports that ls is a lazy singleton
+func (ls *lazySingleton[T]) kind() Kind {
+	if ls.scoped {
+		return KindScoped
+	}
+	return KindLazySingleton
 }
 
 // getInstance returns the singleton instance, creating it if necessary
-func (ls *lazySingleton[T]) getInstance(sl *ServiceLocator) (T, error) {
+func (ls *lazySingleton[T]) getInstance(ctx context.Context, sl *ServiceLocator) (T, bool, error) {
 	if ls.provider == nil {
 		var zero T
-		return zero, fmt.Errorf("no provider registered for type %T", ls.instance)
+		return zero, false, notRegistered(ls.typeKey())
 	}
 
-	ls.once.Do(func() {
-		ls.instance = ls.provider()
-		sl.mu.Lock()
-		sl.instances[getTypeKey[T]()] = ls.instance
-		sl.mu.Unlock()
-	})
-	return ls.instance, nil
+	ls.mu.Lock()
+	if ls.done {
+		ls.mu.Unlock()
+		return ls.instance, false, nil
+	}
+	if ls.failure != nil && (ls.forever || time.Now().Before(ls.retryAt)) {
+		err := ls.failure
+		ls.mu.Unlock()
+		var zero T
+		return zero, false, err
+	}
+
+	// Join the construction in flight, or start one
+	f, leader := ls.flight, false
+	if f == nil {
+		f, leader = &flight[T]{done: make(chan struct{})}, true
+		ls.flight = f
+	}
+	ls.mu.Unlock()
+
+	timeout := sl.opts.constructTimeout
+	if leader {
+		var info *SingletonInfo
+		if sl.opts.goroutineLabels {
+			info = newSingletonInfo(ctx)
+		}
+		if timeout <= 0 {
+			ls.construct(sl, f, info)
+			return f.instance, true, f.err
+		}
+		// The construction outlives callers that time out and its result is
+		// kept, so a later Get returns it without building again
+		go ls.construct(sl, f, info)
+	}
+
+	// Waiting on a construction that waits on this goroutine would never return
+	done, cycle := awaitConstruction(ls.typeKey(), &f.goroutine)
+	if cycle != nil {
+		var zero T
+		return zero, leader, cycleError(cycle, sl.callSite)
+	}
+	defer done()
+
+	if timeout <= 0 {
+		<-f.done
+		return f.instance, false, f.err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-f.done:
+		return f.instance, leader, f.err
+	case <-timer.C:
+		var zero T
+		return zero, leader, fmt.Errorf("construction of type %v exceeded %v", ls.typeKey(), timeout)
+	}
 }
 
-// getTypeKey returns a unique key for type T
-func getTypeKey[T any]() any {
+// buildDuration returns how long the provider took to build the instance, or 0
+// if it has not been built
+func (ls *lazySingleton[T]) buildDuration() time.Duration {
+	return time.Duration(ls.duration.Load())
+}
+
+// typeKey returns the key ls is registered under
+func (ls *lazySingleton[T]) typeKey() any {
+	if ls.key != nil {
+		return ls.key
+	}
+	return getTypeKey[T]()
+}
+
+// construct runs the provider for f and publishes its result
+func (ls *lazySingleton[T]) construct(sl *ServiceLocator, f *flight[T], info *SingletonInfo) {
+	typeKey := ls.typeKey()
+	goroutine, leave := enterConstruction(typeKey)
+	f.goroutine.Store(goroutine)
+	start := time.Now()
+	f.instance, f.err = ls.build(sl.resolving(typeKey))
+	duration := time.Since(start)
+	leave()
+
+	ls.mu.Lock()
+	ls.flight = nil
+	if f.err == nil {
+		ls.instance = f.instance
+		ls.done = true
+		ls.failure, ls.failures = nil, 0
+		ls.duration.Store(int64(duration))
+		if info != nil {
+			info.Time = time.Now()
+			ls.info = info
+		}
+	} else if policy := sl.opts.lazyRetry; policy != nil {
+		ls.failures++
+		wait := policy(ls.failures)
+		ls.failure, ls.forever, ls.retryAt = f.err, wait < 0, time.Now().Add(wait)
+	}
+	ls.mu.Unlock()
+	close(f.done)
+
+	if f.err != nil {
+		return
+	}
+	// Promote the instance so later lookups hit the instances map, but only
+	// while this entry is still the registered provider. A registration that
+	// replaced it during construction must not be shadowed by a stale instance
+	sl.mu.Lock()
+	if current, exists := sl.providers[typeKey]; exists && current == any(ls) {
+		sl.storeInstance(typeKey, ls.instance)
+	}
+	sl.mu.Unlock()
+}
+
+// build runs the provider and its decorators, converting a panic into an error.
+// The provider runs without holding the locator lock so it can call Get
+func (ls *lazySingleton[T]) build(sl *ServiceLocator) (instance T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			instance = zero
+			err = panicError(ls.typeKey(), r)
+		}
+	}()
+	instance, err = ls.provider(sl)
+	if err != nil {
+		return instance, err
+	}
+	return decorateAs(sl, keyType(ls.typeKey()), instance), nil
+}
+
+// panicError converts the value r recovered from the provider of typeKey into an
+// error carrying the stack of the panic, wrapping r if it is an error. It must be
+// called from the deferred function that recovered r
+func panicError(typeKey, r any) error {
+	if rerr, ok := r.(error); ok {
+		return fmt.Errorf("provider for type %v panicked: %w\n%s", typeKey, rerr, debug.Stack())
+	}
+	return fmt.Errorf("provider for type %v panicked: %v\n%s", typeKey, r, debug.Stack())
+}
+
+// metadataProvider selects one of several instances using a key derived from the context
+type metadataProvider[T any] struct {
+	keyFn     func(context.Context) string
+	instances map[string]T
+}
+
+// resolve returns the decorated instance registered under the key derived from ctx
+func (mp *metadataProvider[T]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	instance, err := mp.getInstance(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return decorate(sl, instance), false, nil
+}
+
+// kind reports that mp holds prebuilt singletons
+func (mp *metadataProvider[T]) kind() Kind {
+	return KindSingleton
+}
+
+// getInstance returns the instance registered under the key derived from ctx
+func (mp *metadataProvider[T]) getInstance(ctx context.Context) (T, error) {
+	key := mp.keyFn(ctx)
+	if instance, exists := mp.instances[key]; exists {
+		return instance, nil
+	}
+
 	var zero T
-	return reflect.TypeOf(zero)
+	return zero, fmt.Errorf("no instance registered for key %q for type %v", key, getTypeKey[T]())
+}
+
+// getTypeKey returns a unique key for type T. Unlike reflect.TypeOf on a zero
+// value it also works for interface types. It is not cached: reflect.TypeOf of a
+// constant pointer neither allocates nor locks, and measures about a third of the
+// cost of a sync.Map lookup keyed by T, as BenchmarkTypeKeyCached shows
+func getTypeKey[T any]() any {
+	return reflect.TypeOf((*T)(nil)).Elem()
 }
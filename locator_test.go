@@ -1,9 +1,17 @@
 package locator_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/RobinHood3082/locator"
 )
@@ -196,6 +204,125 @@ func TestLazySingletonMultipleRetrievals(t *testing.T) {
 	}
 }
 
+// Test WithPanicRecovery turns a panicking factory into an error with the stack
+func TestPanicRecovery(t *testing.T) {
+	sl := locator.New(locator.WithPanicRecovery())
+
+	var fail bool
+	locator.RegisterFactory(sl, func() *TestService {
+		if fail {
+			panic("connection refused")
+		}
+		return &TestService{Name: "Service"}
+	})
+	errBoom := errors.New("boom")
+	locator.RegisterFactory(sl, func() *AnotherTestService { panic(errBoom) })
+
+	fail = true
+	_, err := locator.Get[*TestService](sl)
+	if err == nil || !strings.Contains(err.Error(), "provider for type *locator_test.TestService panicked: connection refused") {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "TestPanicRecovery") {
+		t.Fatalf("expected the stack of the panic, got %v", err)
+	}
+	if _, err := locator.Get[*AnotherTestService](sl); !errors.Is(err, errBoom) {
+		t.Fatalf("expected panic error wrapping boom, got %v", err)
+	}
+
+	fail = false
+	if _, err := locator.Get[*TestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected the panic to escape without WithPanicRecovery")
+		}
+	}()
+	plain := locator.New()
+	locator.RegisterFactory(plain, func() *AnotherTestService { panic(errBoom) })
+	locator.Get[*AnotherTestService](plain)
+}
+
+// Test a panicking lazy singleton provider reports an error and is retried
+func TestLazySingletonPanicRetry(t *testing.T) {
+	sl := locator.New()
+
+	errBoom := errors.New("boom")
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		if callCount == 1 {
+			panic(errBoom)
+		}
+		return &TestService{Name: "Recovered"}
+	})
+
+	_, err := locator.Get[*TestService](sl)
+	if !errors.Is(err, errBoom) || !strings.Contains(err.Error(), "panicked") {
+		t.Fatalf("expected panic error wrapping boom, got %v", err)
+	}
+
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error on retry, got %v", err)
+	}
+	if service.Name != "Recovered" {
+		t.Fatalf("expected Recovered, got %v", service.Name)
+	}
+
+	locator.Get[*TestService](sl)
+	if callCount != 2 {
+		t.Fatalf("expected provider to be called twice, got %d", callCount)
+	}
+}
+
+// Test waiting for a slow lazy singleton times out while construction continues
+func TestLazySingletonConstructTimeout(t *testing.T) {
+	sl := locator.New(locator.WithConstructTimeout(10 * time.Millisecond))
+
+	var callCount atomic.Int32
+	release := make(chan struct{})
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount.Add(1)
+		<-release
+		return &TestService{Name: "Slow"}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := locator.Get[*TestService](sl)
+			if err == nil || !strings.Contains(err.Error(), "exceeded 10ms") {
+				t.Errorf("expected timeout error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(release)
+
+	// The abandoned construction completes and its instance is kept
+	deadline := time.Now().Add(time.Second)
+	for {
+		service, err := locator.Get[*TestService](sl)
+		if err == nil {
+			if service.Name != "Slow" {
+				t.Fatalf("expected Slow, got %s", service.Name)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected construction to complete, got %v", err)
+		}
+	}
+	if callCount.Load() != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount.Load())
+	}
+}
+
 // Test registering and retrieving value types
 func TestValueTypes(t *testing.T) {
 	sl := locator.New()
@@ -289,6 +416,74 @@ func TestNilProvider(t *testing.T) {
 	}
 }
 
+// Test a nil provider is not registered
+func TestNilProviderSkipped(t *testing.T) {
+	sl := locator.New()
+
+	singletonInstance := &TestService{Name: "Existing"}
+	locator.RegisterSingleton(sl, singletonInstance)
+	locator.RegisterFactory[*TestService](sl, nil)
+	if service, err := locator.Get[*TestService](sl); err != nil || service != singletonInstance {
+		t.Fatalf("expected the existing registration to be kept, got %v, %v", service, err)
+	}
+
+	if installed := locator.RegisterDefaultLazySingleton[*AnotherTestService](sl, nil); installed {
+		t.Fatalf("expected a nil default not to be installed")
+	}
+	if err := locator.Validate(sl, (*AnotherTestService)(nil)); err == nil {
+		t.Fatalf("expected AnotherTestService to stay unregistered")
+	}
+}
+
+// Test strict registration panics on a nil provider
+func TestStrictRegistration(t *testing.T) {
+	sl := locator.New(locator.WithStrictRegistration())
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || err.Error() != "nil provider for type *locator_test.TestService" {
+			t.Fatalf("expected nil provider panic, got %v", r)
+		}
+	}()
+	locator.RegisterLazySingleton[*TestService](sl, nil)
+	t.Fatalf("expected a panic")
+}
+
+// Test strict overwrite rejects a second registration, naming both call sites
+func TestStrictOverwrite(t *testing.T) {
+	sl := locator.New(locator.WithStrictOverwrite())
+
+	locator.RegisterSingleton(sl, &TestService{Name: "First"})
+	locator.RegisterPlatform(sl, "linux", &AnotherTestService{ID: 1})
+	locator.RegisterPlatform(sl, "darwin", &AnotherTestService{ID: 2})
+	if err := locator.RegisterVersioned(sl, "1.0.0", 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := locator.RegisterVersioned(sl, "1.0.0", 2); !errors.Is(err, locator.ErrDuplicateRegistration) {
+		t.Fatalf("expected %v, got %v", locator.ErrDuplicateRegistration, err)
+	}
+
+	// Overriding is deliberate and stays allowed
+	restore := locator.Override(sl, &TestService{Name: "Double"})
+	restore()
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, locator.ErrDuplicateRegistration) {
+			t.Fatalf("expected %v panic, got %v", locator.ErrDuplicateRegistration, err)
+		}
+		sites := regexp.MustCompile(`locator_test\.go:\d+`).FindAllString(err.Error(), -1)
+		if len(sites) != 2 || sites[0] == sites[1] {
+			t.Fatalf("expected both call sites, got %v", err)
+		}
+		if service, _ := locator.Get[*TestService](sl); service.Name != "First" {
+			t.Fatalf("expected the first registration to be kept, got %v", service.Name)
+		}
+	}()
+	locator.RegisterFactory(sl, func() *TestService { return &TestService{Name: "Second"} })
+}
+
 // Test type safety
 func TestTypeSafety(t *testing.T) {
 	sl := locator.New()
@@ -304,3 +499,927 @@ func TestTypeSafety(t *testing.T) {
 		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
 	}
 }
+
+type regionKey struct{}
+
+// Test RegisterByMetadata and GetCtx
+func TestRegisterByMetadata(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterByMetadata(sl, func(ctx context.Context) string {
+		region, _ := ctx.Value(regionKey{}).(string)
+		return region
+	}, map[string]*TestService{
+		"eu": {Name: "EU"},
+		"us": {Name: "US"},
+	})
+
+	euCtx := context.WithValue(context.Background(), regionKey{}, "eu")
+	service, err := locator.GetCtx[*TestService](euCtx, sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "EU" {
+		t.Fatalf("expected EU, got %v", service.Name)
+	}
+
+	usCtx := context.WithValue(context.Background(), regionKey{}, "us")
+	service, err = locator.GetCtx[*TestService](usCtx, sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "US" {
+		t.Fatalf("expected US, got %v", service.Name)
+	}
+
+	apCtx := context.WithValue(context.Background(), regionKey{}, "ap")
+	_, err = locator.GetCtx[*TestService](apCtx, sl)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectedError := `no instance registered for key "ap" for type *locator_test.TestService`
+	if err.Error() != expectedError {
+		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+}
+
+// Test a nil key function is rejected like a nil provider
+func TestRegisterByMetadataNilKeyFn(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterByMetadata(sl, nil, map[string]*TestService{"eu": {Name: "EU"}})
+	if locator.Has[*TestService](sl) {
+		t.Fatalf("expected the type to stay unregistered")
+	}
+	if _, err := locator.GetCtx[*TestService](context.Background(), sl); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	strict := locator.New(locator.WithStrictRegistration())
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected nil provider panic, got nil")
+		}
+	}()
+	locator.RegisterByMetadata(strict, nil, map[string]*TestService{"eu": {Name: "EU"}})
+}
+
+// Test that a lazy singleton provider runs exactly once under heavy contention
+func TestLazySingletonStress(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		if atomic.AddInt32(&callCount, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return &TestService{Name: "LazySingleton"}
+	})
+
+	const goroutines = 200
+	results := make(chan *TestService, goroutines)
+	var wg sync.WaitGroup
+	resolve := func() {
+		defer wg.Done()
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		results <- service
+	}
+
+	// Half of the callers race for the first construction, the other half
+	// arrive while the provider is still running
+	wg.Add(goroutines)
+	for i := 0; i < goroutines/2; i++ {
+		go resolve()
+	}
+	<-started
+	for i := 0; i < goroutines/2; i++ {
+		go resolve()
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+
+	var first *TestService
+	for service := range results {
+		if first == nil {
+			first = service
+		}
+		if service != first {
+			t.Fatalf("expected the same instance for every caller")
+		}
+	}
+
+	if n := atomic.LoadInt32(&callCount); n != 1 {
+		t.Fatalf("expected provider to be called once, got %d", n)
+	}
+}
+
+// Test that registering a different kind replaces the previous registration
+func TestRegistrationReplacesOtherKind(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Singleton"})
+	locator.RegisterFactory(sl, func() *TestService {
+		return &TestService{Name: "Factory"}
+	})
+
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Factory" {
+		t.Fatalf("expected Factory, got %v", service.Name)
+	}
+
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		return &TestService{Name: "Lazy"}
+	})
+	if _, err := locator.Get[*TestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Replacement"})
+	service, err = locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Replacement" {
+		t.Fatalf("expected Replacement, got %v", service.Name)
+	}
+}
+
+// Test Clone produces an independent copy of the registrations
+func TestClone(t *testing.T) {
+	sl := locator.New()
+
+	singletonInstance := &TestService{Name: "Singleton"}
+	locator.RegisterSingleton(sl, singletonInstance)
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		return &AnotherTestService{ID: 1}
+	})
+
+	original, err := locator.Get[*AnotherTestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	clone := sl.Clone()
+	locator.RegisterSingleton(clone, 42)
+
+	if _, err := locator.Get[int](sl); err == nil {
+		t.Fatalf("expected registration on the clone not to affect the original")
+	}
+
+	service, err := locator.Get[*TestService](clone)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service != singletonInstance {
+		t.Fatalf("expected the clone to share the eager singleton")
+	}
+
+	cloned, err := locator.Get[*AnotherTestService](clone)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cloned == original {
+		t.Fatalf("expected the clone to construct its own lazy singleton")
+	}
+
+	again, err := locator.Get[*AnotherTestService](clone)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if again != cloned {
+		t.Fatalf("expected the cloned lazy singleton to be cached")
+	}
+
+	shared := sl.Clone(locator.ShareSingletons())
+	if service, err := locator.Get[*AnotherTestService](shared); err != nil || service != original {
+		t.Fatalf("expected the clone to share the built lazy singleton, got %v, %v", service, err)
+	}
+}
+
+// Test EstimateSize reports one entry per materialized singleton
+func TestEstimateSize(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, TestService{Name: "Value"})
+	locator.RegisterSingleton(sl, 42)
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		return &AnotherTestService{ID: 1}
+	})
+	locator.RegisterFactory(sl, func() string {
+		return "factory"
+	})
+
+	sizes := locator.EstimateSize(sl)
+	if len(sizes) != 2 {
+		t.Fatalf("expected 2 entries before the lazy singleton is built, got %d", len(sizes))
+	}
+
+	if _, err := locator.Get[*AnotherTestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := locator.Get[string](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sizes = locator.EstimateSize(sl)
+	if len(sizes) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(sizes))
+	}
+	if size := sizes[reflect.TypeOf(TestService{})]; size != unsafe.Sizeof(TestService{}) {
+		t.Fatalf("expected %d for TestService, got %d", unsafe.Sizeof(TestService{}), size)
+	}
+	if size := sizes[reflect.TypeOf(0)]; size != unsafe.Sizeof(0) {
+		t.Fatalf("expected %d for int, got %d", unsafe.Sizeof(0), size)
+	}
+	if size := sizes[reflect.TypeOf(&AnotherTestService{})]; size != unsafe.Sizeof(uintptr(0)) {
+		t.Fatalf("expected pointer size for *AnotherTestService, got %d", size)
+	}
+}
+
+// Test GetOr and GetOrElse fall back only when resolution fails
+func TestGetOr(t *testing.T) {
+	sl := locator.New()
+
+	fallback := &TestService{Name: "Fallback"}
+	if service := locator.GetOr(sl, fallback); service != fallback {
+		t.Fatalf("expected fallback, got %v", service)
+	}
+
+	var fallbackCalls int
+	service := locator.GetOrElse(sl, func() *TestService {
+		fallbackCalls++
+		return fallback
+	})
+	if service != fallback || fallbackCalls != 1 {
+		t.Fatalf("expected fallback to be built once, got %v after %d calls", service, fallbackCalls)
+	}
+
+	// A provider returning the zero value is still a successful resolution
+	locator.RegisterFactory(sl, func() *TestService {
+		return nil
+	})
+	if service := locator.GetOr(sl, fallback); service != nil {
+		t.Fatalf("expected nil from the provider, got %v", service)
+	}
+	locator.GetOrElse(sl, func() *TestService {
+		fallbackCalls++
+		return fallback
+	})
+	if fallbackCalls != 1 {
+		t.Fatalf("expected fallback not to be called for a registered type")
+	}
+}
+
+type DependentService struct {
+	Service *TestService
+	Another *AnotherTestService
+}
+
+// Test providers that receive the locator to resolve their own dependencies
+func TestProvidersWithLocator(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Dependency"})
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *AnotherTestService {
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		return &AnotherTestService{ID: len(service.Name)}
+	})
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *DependentService {
+		// Resolving a lazy singleton from inside a provider must not deadlock
+		another, err := locator.Get[*AnotherTestService](sl)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		return &DependentService{Service: service, Another: another}
+	})
+
+	dependent, err := locator.Get[*DependentService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dependent.Service.Name != "Dependency" {
+		t.Fatalf("expected Dependency, got %v", dependent.Service.Name)
+	}
+	if dependent.Another.ID != len("Dependency") {
+		t.Fatalf("expected ID %d, got %v", len("Dependency"), dependent.Another.ID)
+	}
+
+	again, err := locator.Get[*DependentService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if again == dependent {
+		t.Fatalf("expected different instances, got the same")
+	}
+	if again.Another != dependent.Another {
+		t.Fatalf("expected the lazy dependency to be shared")
+	}
+}
+
+// Test that cloned locator-aware providers resolve from the clone
+func TestProvidersWithLocatorClone(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Original"})
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *DependentService {
+		service, _ := locator.Get[*TestService](sl)
+		return &DependentService{Service: service}
+	})
+
+	clone := sl.Clone()
+	locator.RegisterSingleton(clone, &TestService{Name: "Clone"})
+
+	dependent, err := locator.Get[*DependentService](clone)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dependent.Service.Name != "Clone" {
+		t.Fatalf("expected Clone, got %v", dependent.Service.Name)
+	}
+}
+
+type Config struct {
+	Debug bool
+}
+
+// Test GetNonNil substitutes the default for a nil registration
+func TestGetNonNil(t *testing.T) {
+	sl := locator.New()
+
+	def := &Config{Debug: true}
+	locator.RegisterSingleton[*Config](sl, nil)
+
+	config, err := locator.GetNonNil(sl, def)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config != def {
+		t.Fatalf("expected the default, got %v", config)
+	}
+
+	registered := &Config{}
+	locator.RegisterSingleton(sl, registered)
+	config, err = locator.GetNonNil(sl, def)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config != registered {
+		t.Fatalf("expected the registered config, got %v", config)
+	}
+
+	if _, err := locator.GetNonNil(sl, &TestService{}); err == nil {
+		t.Fatalf("expected error for an unregistered type, got nil")
+	}
+}
+
+// Test ResetInstances rebuilds lazy singletons while keeping registrations
+func TestResetInstances(t *testing.T) {
+	sl := locator.New()
+
+	singletonInstance := &AnotherTestService{ID: 1}
+	locator.RegisterSingleton(sl, singletonInstance)
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: fmt.Sprintf("Instance%d", callCount)}
+	})
+
+	first, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sl.ResetInstances()
+
+	second, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if second == first || second.Name != "Instance2" {
+		t.Fatalf("expected a rebuilt instance, got %v", second.Name)
+	}
+
+	third, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if third != second {
+		t.Fatalf("expected the rebuilt instance to be cached again")
+	}
+
+	singleton, err := locator.Get[*AnotherTestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if singleton != singletonInstance {
+		t.Fatalf("expected the eager singleton to survive Reset")
+	}
+}
+
+// Test TryGet with the comma-ok idiom
+func TestTryGet(t *testing.T) {
+	sl := locator.New()
+
+	if _, ok := locator.TryGet[*TestService](sl); ok {
+		t.Fatalf("expected false for an unregistered type")
+	}
+
+	locator.RegisterLazySingleton[*TestService](sl, nil)
+	if _, ok := locator.TryGet[*TestService](sl); ok {
+		t.Fatalf("expected false for a failing provider")
+	}
+
+	singletonInstance := &TestService{Name: "Singleton"}
+	locator.RegisterSingleton(sl, singletonInstance)
+	service, ok := locator.TryGet[*TestService](sl)
+	if !ok {
+		t.Fatalf("expected true for a registered type")
+	}
+	if service != singletonInstance {
+		t.Fatalf("expected %v, got %v", singletonInstance, service)
+	}
+}
+
+// Test composite value types do not collide
+func TestCompositeValueTypes(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterValue(sl, []string{"a", "b"})
+	locator.RegisterValue(sl, []int{1, 2, 3})
+	locator.RegisterValue(sl, map[string]int{"one": 1})
+	locator.RegisterValue(sl, map[string]string{"one": "1"})
+	locator.RegisterValue(sl, TestService{Name: "Value"})
+
+	strs, err := locator.Get[[]string](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(strs, []string{"a", "b"}) {
+		t.Fatalf("expected [a b], got %v", strs)
+	}
+
+	ints, err := locator.Get[[]int](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", ints)
+	}
+
+	intMap, err := locator.Get[map[string]int](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if intMap["one"] != 1 {
+		t.Fatalf("expected 1, got %v", intMap["one"])
+	}
+
+	strMap, err := locator.Get[map[string]string](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strMap["one"] != "1" {
+		t.Fatalf("expected \"1\", got %v", strMap["one"])
+	}
+
+	value, err := locator.Get[TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value.Name != "Value" {
+		t.Fatalf("expected Value, got %v", value.Name)
+	}
+
+	if _, err := locator.Get[*TestService](sl); err == nil {
+		t.Fatalf("expected a struct value registration not to satisfy its pointer type")
+	}
+	if _, err := locator.Get[[]*TestService](sl); err == nil {
+		t.Fatalf("expected error for an unregistered slice type, got nil")
+	}
+}
+
+// Test GetChecked verifies the resolved instance implements the interface
+func TestGetChecked(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &wrappedGreeter{inner: baseGreeter{}, tag: "checked"})
+	locator.RegisterSingleton(sl, &TestService{Name: "Unchecked"})
+
+	greeter, err := locator.GetChecked[*wrappedGreeter, Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "checked(hello)" {
+		t.Fatalf("expected checked(hello), got %s", greeter.Greet())
+	}
+
+	_, err = locator.GetChecked[*TestService, Greeter](sl)
+	if err == nil || !strings.Contains(err.Error(), "locator_test.Greeter") {
+		t.Fatalf("expected error naming locator_test.Greeter, got %v", err)
+	}
+}
+
+type Repository interface {
+	Find(id int) string
+}
+
+type PostgresRepo struct{}
+
+func (*PostgresRepo) Find(id int) string { return fmt.Sprintf("row %d", id) }
+
+// Test binding a concrete type to an interface key
+func TestRegisterAs(t *testing.T) {
+	sl := locator.New()
+
+	repo := &PostgresRepo{}
+	locator.RegisterAs[Repository](sl, repo)
+	locator.RegisterSingleton[Greeter](sl, baseGreeter{})
+
+	resolved, err := locator.Get[Repository](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolved != repo {
+		t.Fatalf("expected the bound instance, got %v", resolved)
+	}
+
+	// Distinct interfaces have distinct keys
+	greeter, err := locator.Get[Greeter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if greeter.Greet() != "hello" {
+		t.Fatalf("expected hello, got %s", greeter.Greet())
+	}
+	if _, err := locator.Get[Handler](sl); err == nil || err.Error() != "no provider registered for type locator_test.Handler" {
+		t.Fatalf("expected unregistered interface error, got %v", err)
+	}
+
+	// The concrete type is not registered by the binding
+	if _, err := locator.Get[*PostgresRepo](sl); err == nil {
+		t.Fatalf("expected error for the concrete type, got nil")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected a panic binding a type that does not implement the interface")
+		}
+	}()
+	locator.RegisterAs[Repository](sl, &TestService{})
+}
+
+// Test error-returning providers propagate their errors from Get
+func TestProviderE(t *testing.T) {
+	sl := locator.New()
+
+	errDial := errors.New("dial failed")
+	var attempts int
+	locator.RegisterLazySingletonE(sl, func() (*TestService, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errDial
+		}
+		return &TestService{Name: "Connected"}, nil
+	})
+	locator.RegisterFactoryE(sl, func() (*AnotherTestService, error) {
+		return nil, errDial
+	})
+
+	if _, err := locator.Get[*TestService](sl); !errors.Is(err, errDial) {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error on retry, got %v", err)
+	}
+	if service.Name != "Connected" {
+		t.Fatalf("expected Connected, got %s", service.Name)
+	}
+
+	if _, err := locator.Get[*AnotherTestService](sl); !errors.Is(err, errDial) {
+		t.Fatalf("expected dial error from factory, got %v", err)
+	}
+}
+
+// Test providers receiving the locator resolve their dependencies and report failures
+func TestProviderWith(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterLazySingletonWith(sl, func(sl *locator.ServiceLocator) (*AnotherTestService, error) {
+		service, err := locator.Get[*TestService](sl)
+		if err != nil {
+			return nil, fmt.Errorf("dependency: %w", err)
+		}
+		return &AnotherTestService{ID: len(service.Name)}, nil
+	})
+	locator.RegisterFactoryWith(sl, func(sl *locator.ServiceLocator) (string, error) {
+		service, err := locator.Get[*AnotherTestService](sl)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("id %d", service.ID), nil
+	})
+
+	_, err := locator.Get[string](sl)
+	if err == nil || !strings.Contains(err.Error(), "dependency: no provider registered for type *locator_test.TestService") {
+		t.Fatalf("expected the missing dependency error, got %v", err)
+	}
+
+	locator.RegisterSingleton(sl, &TestService{Name: "Four"})
+	description, err := locator.Get[string](sl)
+	if err != nil {
+		t.Fatalf("expected no error on retry, got %v", err)
+	}
+	if description != "id 4" {
+		t.Fatalf("expected id 4, got %s", description)
+	}
+	if graph := sl.DependencyGraph(); len(graph["string"]) != 1 {
+		t.Fatalf("expected the factory dependency to be recorded, got %v", graph)
+	}
+}
+
+// Test context-aware factories receive the resolution context
+func TestFactoryCtx(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterFactoryCtx(sl, func(ctx context.Context) (*TestService, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		region, _ := ctx.Value(regionKey{}).(string)
+		return &TestService{Name: region}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), regionKey{}, "eu")
+	service, err := locator.GetCtx[*TestService](ctx, sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "eu" {
+		t.Fatalf("expected eu, got %s", service.Name)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := locator.GetCtx[*TestService](canceled, sl); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// Test MustGet returns the instance or panics with the resolution error
+func TestMustGet(t *testing.T) {
+	sl := locator.New()
+	singletonInstance := &TestService{Name: "Must"}
+	locator.RegisterSingleton(sl, singletonInstance)
+
+	if service := locator.MustGet[*TestService](sl); service != singletonInstance {
+		t.Fatalf("expected %v, got %v", singletonInstance, service)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		expected := "cannot resolve *locator_test.AnotherTestService: no provider registered for type *locator_test.AnotherTestService"
+		if !ok || err.Error() != expected {
+			t.Fatalf("expected panic %q, got %v", expected, err)
+		}
+	}()
+	locator.MustGet[*AnotherTestService](sl)
+	t.Fatalf("expected a panic")
+}
+
+// Test Has reports registrations without running providers
+func TestHas(t *testing.T) {
+	sl := locator.New()
+
+	if locator.Has[*TestService](sl) {
+		t.Fatalf("expected false for an unregistered type")
+	}
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{}
+	})
+	locator.RegisterSingleton[Greeter](sl, baseGreeter{})
+	if !locator.Has[*TestService](sl) || !locator.Has[Greeter](sl) {
+		t.Fatalf("expected true for registered types")
+	}
+	if callCount != 0 {
+		t.Fatalf("expected no provider calls, got %d", callCount)
+	}
+
+	scope := sl.Scope()
+	if !locator.Has[*TestService](scope) {
+		t.Fatalf("expected a scope to report inherited registrations")
+	}
+}
+
+// Test Unregister removes the provider and the cached instance
+func TestUnregister(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.Get[*TestService](sl)
+
+	if !locator.Unregister[*TestService](sl) {
+		t.Fatalf("expected Unregister to report the registration")
+	}
+	if locator.Has[*TestService](sl) {
+		t.Fatalf("expected the type to be unregistered")
+	}
+	if _, err := locator.Get[*TestService](sl); err == nil {
+		t.Fatalf("expected error after Unregister, got nil")
+	}
+	if locator.Unregister[*TestService](sl) {
+		t.Fatalf("expected false for an unregistered type")
+	}
+
+	// A new registration works as usual
+	locator.RegisterSingleton(sl, &TestService{Name: "Again"})
+	if service, err := locator.Get[*TestService](sl); err != nil || service.Name != "Again" {
+		t.Fatalf("expected Again, got %v, %v", service, err)
+	}
+}
+
+// Test Override swaps in a double and restores the previous registration
+func TestOverride(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: "Real"}
+	})
+	original, _ := locator.Get[*TestService](sl)
+
+	restore := locator.Override(sl, &TestService{Name: "Double"})
+	if service, err := locator.Get[*TestService](sl); err != nil || service.Name != "Double" {
+		t.Fatalf("expected Double, got %v, %v", service, err)
+	}
+
+	restore()
+	restore()
+	service, err := locator.Get[*TestService](sl)
+	if err != nil || service != original {
+		t.Fatalf("expected the original instance, got %v, %v", service, err)
+	}
+	if callCount != 1 {
+		t.Fatalf("expected the lazy singleton to stay built, got %d provider calls", callCount)
+	}
+
+	// Overriding an unregistered type restores to unregistered
+	restore = locator.Override(sl, &AnotherTestService{ID: 7})
+	restore()
+	if locator.Has[*AnotherTestService](sl) {
+		t.Fatalf("expected the type to be unregistered again")
+	}
+}
+
+// Test Reset drops every registration
+func TestReset(t *testing.T) {
+	sl := locator.New(locator.WithPlatform("linux"))
+
+	locator.RegisterSingleton(sl, &AnotherTestService{ID: 1})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.RegisterImplementors[Handler](sl, &UpperHandler{})
+	locator.Decorate(sl, wrapWith("decorated"))
+	locator.Get[*TestService](sl)
+
+	sl.Reset()
+
+	if locator.Has[*AnotherTestService](sl) || locator.Has[*TestService](sl) {
+		t.Fatalf("expected no registrations after Reset")
+	}
+	if handlers := locator.ResolveImplementors[Handler](sl); len(handlers) != 0 {
+		t.Fatalf("expected no group members after Reset, got %d", len(handlers))
+	}
+	if stats := sl.Stats(); stats.Providers != 0 || stats.Instances != 0 || len(stats.Resolutions) != 0 {
+		t.Fatalf("expected empty stats after Reset, got %+v", stats)
+	}
+
+	// Decorators are gone and options are kept
+	locator.RegisterPlatform(sl, "linux", &TestService{Name: "Linux"})
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Linux" {
+		t.Fatalf("expected Linux, got %s", service.Name)
+	}
+}
+
+// Test Replace swaps a singleton while it is being resolved concurrently
+func TestReplace(t *testing.T) {
+	sl := locator.New()
+
+	if _, err := locator.Replace(sl, &TestService{}); err == nil {
+		t.Fatalf("expected error for an unregistered type, got nil")
+	}
+	locator.RegisterFactory(sl, func() *AnotherTestService { return &AnotherTestService{} })
+	if _, err := locator.Replace(sl, &AnotherTestService{}); err == nil || !strings.Contains(err.Error(), "registered as factory") {
+		t.Fatalf("expected factory error, got %v", err)
+	}
+
+	first := &TestService{Name: "v1"}
+	locator.RegisterSingleton(sl, first)
+	sl.Freeze()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				service, err := locator.Get[*TestService](sl)
+				if err != nil || service == nil || service.Name == "" {
+					t.Errorf("expected a complete instance, got %v, %v", service, err)
+					return
+				}
+			}
+		}()
+	}
+
+	current := first
+	for i := 2; i <= 50; i++ {
+		next := &TestService{Name: fmt.Sprintf("v%d", i)}
+		previous, err := locator.Replace(sl, next)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if previous != current {
+			t.Fatalf("expected previous %v, got %v", current, previous)
+		}
+		current = next
+	}
+	close(stop)
+	wg.Wait()
+
+	if service, _ := locator.Get[*TestService](sl); service != current {
+		t.Fatalf("expected the latest instance, got %v", service)
+	}
+}
+
+// Test a slow lazy provider blocks neither registrations nor resolutions of other
+// types
+func TestSlowProviderDoesNotBlockOthers(t *testing.T) {
+	sl := locator.New()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		close(started)
+		<-release
+		return &AnotherTestService{ID: 1}
+	})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{Name: "Service"} })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := locator.Get[*AnotherTestService](sl)
+		done <- err
+	}()
+	<-started
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for i := 0; i < 100; i++ {
+			locator.RegisterSingletonNamed(sl, fmt.Sprint(i), i)
+			if _, err := locator.Get[*TestService](sl); err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+		}
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected other types to resolve while a provider is running")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
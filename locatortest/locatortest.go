@@ -0,0 +1,100 @@
+// Package locatortest provides helpers for tests using a locator
+package locatortest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// New creates a locator configured by opts whose singletons are shut down when
+// the test and its subtests complete. Shutdown errors fail the test
+func New(t testing.TB, opts ...locator.Option) *locator.ServiceLocator {
+	t.Helper()
+	sl := locator.New(opts...)
+	t.Cleanup(func() {
+		if err := sl.Shutdown(context.Background()); err != nil {
+			t.Errorf("locator shutdown: %v", err)
+		}
+	})
+	return sl
+}
+
+// RequireRegistered fails the test immediately if T is not registered in sl
+func RequireRegistered[T any](t testing.TB, sl *locator.ServiceLocator) {
+	t.Helper()
+	if !locator.Has[T](sl) {
+		t.Fatalf("expected %v to be registered", typeOf[T]())
+	}
+}
+
+// Recorder records the types resolved from a locator, so a test can assert
+// which services a code path actually requested
+type Recorder struct {
+	mu    sync.Mutex
+	types []reflect.Type
+}
+
+// Record starts recording every resolution made from sl, including those made by
+// providers for their dependencies
+func Record(sl *locator.ServiceLocator) *Recorder {
+	r := &Recorder{}
+	sl.OnResolve(func(typ reflect.Type, _ any, _ time.Duration, _ error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.types = append(r.types, typ)
+	})
+	return r
+}
+
+// Requested returns the resolved types in the order they were requested, with
+// repetitions
+func (r *Recorder) Requested() []reflect.Type {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]reflect.Type(nil), r.types...)
+}
+
+// Reset forgets the resolutions recorded so far
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = nil
+}
+
+// RequireRequested fails the test immediately if T was not resolved since
+// recording started or was last reset
+func RequireRequested[T any](t testing.TB, r *Recorder) {
+	t.Helper()
+	if !r.requested(typeOf[T]()) {
+		t.Fatalf("expected %v to be requested, got %v", typeOf[T](), r.Requested())
+	}
+}
+
+// RequireNotRequested fails the test immediately if T was resolved since
+// recording started or was last reset
+func RequireNotRequested[T any](t testing.TB, r *Recorder) {
+	t.Helper()
+	if r.requested(typeOf[T]()) {
+		t.Fatalf("expected %v not to be requested, got %v", typeOf[T](), r.Requested())
+	}
+}
+
+// requested reports whether typ was recorded
+func (r *Recorder) requested(typ reflect.Type) bool {
+	for _, requested := range r.Requested() {
+		if requested == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the type T, which may be an interface
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,91 @@
+package locatortest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+	"github.com/RobinHood3082/locator/locatortest"
+)
+
+type Config struct{}
+
+type Server struct{}
+
+// Closer records whether it was closed
+type Closer struct {
+	closed bool
+}
+
+func (c *Closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+// fakeT records fatal failures instead of stopping the test
+type fakeT struct {
+	testing.TB
+	failure string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.failure = fmt.Sprintf(format, args...)
+}
+
+// Test New shuts the locator down when the test completes
+func TestNew(t *testing.T) {
+	closer := &Closer{}
+	t.Run("sub", func(t *testing.T) {
+		sl := locatortest.New(t)
+		locator.RegisterSingleton(sl, closer)
+	})
+	if !closer.closed {
+		t.Fatalf("expected the singleton to be closed after the test")
+	}
+}
+
+// Test RequireRegistered fails for unregistered types only
+func TestRequireRegistered(t *testing.T) {
+	sl := locatortest.New(t)
+	locator.RegisterSingleton(sl, &Config{})
+
+	ft := &fakeT{TB: t}
+	locatortest.RequireRegistered[*Config](ft, sl)
+	if ft.failure != "" {
+		t.Fatalf("expected no failure, got %q", ft.failure)
+	}
+	locatortest.RequireRegistered[*Server](ft, sl)
+	if ft.failure != "expected *locatortest_test.Server to be registered" {
+		t.Fatalf("expected a failure for Server, got %q", ft.failure)
+	}
+}
+
+// Test the recorder sees the services a code path requested
+func TestRecorder(t *testing.T) {
+	sl := locatortest.New(t)
+	locator.RegisterSingleton(sl, &Config{})
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *Server {
+		locator.Get[*Config](sl)
+		return &Server{}
+	})
+
+	recorder := locatortest.Record(sl)
+	locator.GetCtx[*Server](context.Background(), sl)
+
+	locatortest.RequireRequested[*Server](t, recorder)
+	locatortest.RequireRequested[*Config](t, recorder)
+	if requested := recorder.Requested(); len(requested) != 2 {
+		t.Fatalf("expected 2 resolutions, got %v", requested)
+	}
+
+	recorder.Reset()
+	locatortest.RequireNotRequested[*Config](t, recorder)
+	ft := &fakeT{TB: t}
+	locatortest.RequireRequested[*Config](ft, recorder)
+	if ft.failure == "" {
+		t.Fatalf("expected a failure after Reset")
+	}
+}
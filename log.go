@@ -0,0 +1,14 @@
+package locator
+
+import "time"
+
+// logger receives the events of a locator configured with WithLogger. It keeps
+// the locator free of the log/slog dependency, which needs Go 1.21
+type logger interface {
+	// registered is called for each accepted registration while sl.mu is held
+	registered(typeKey any, ev RegisterEvent)
+	// constructed is called when a lazy singleton or scoped instance is first built
+	constructed(typeKey any, kind Kind, d time.Duration)
+	// failed is called when a resolution fails
+	failed(typeKey any, err error)
+}
@@ -0,0 +1,85 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Middleware wraps the construction of an instance. typ names the type being
+// resolved and next runs the registered provider, so a middleware can act before
+// construction, inspect or replace the built value, or return a value without
+// calling next at all, for example from an external cache. The returned value must
+// be of the resolved type, otherwise the resolution fails
+type Middleware func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error)
+
+// resolutionKey is the context key under which runResolver records the kind of
+// the registration being resolved and whether a provider ran
+type resolutionKey struct{}
+
+// resolution is the state runResolver shares with the middlewares through the
+// context
+type resolution struct {
+	kind  Kind
+	built bool
+}
+
+// ProviderRan reports, in a Middleware once next has returned, whether next ran a
+// provider. It is false when next returned an instance that already existed, as
+// for a lazy singleton built concurrently or a cached factory hit, or looked one
+// up, as for aliases and platform or versioned registrations. ctx is the context
+// the middleware received or one derived from it
+func ProviderRan(ctx context.Context) bool {
+	res, _ := ctx.Value(resolutionKey{}).(*resolution)
+	return res != nil && res.built
+}
+
+// ResolverKind reports, in a Middleware, the lifetime of the registration being
+// resolved. It is false outside of a middleware. ctx is the context the middleware
+// received or one derived from it
+func ResolverKind(ctx context.Context) (Kind, bool) {
+	res, ok := ctx.Value(resolutionKey{}).(*resolution)
+	if !ok {
+		return KindUnregistered, false
+	}
+	return res.kind, true
+}
+
+// runResolver runs r through the configured middlewares. Instances already held by
+// the locator, such as materialized singletons, never reach the middlewares
+func (sl *ServiceLocator) runResolver(ctx context.Context, typeKey any, r resolver) (instance any, built bool, err error) {
+	if sl.opts.locatorCheck != nil {
+		defer providerRuns.push(providerRun{registry: sl.registry, typeKey: typeKey})()
+	}
+	if sl.opts.panicRecovery {
+		defer func() {
+			if v := recover(); v != nil {
+				instance, err = nil, panicError(typeKey, v)
+			}
+		}()
+	}
+	if len(sl.opts.middlewares) == 0 {
+		return r.resolve(ctx, sl)
+	}
+
+	// res.built stays false when a middleware short-circuits without calling next
+	res := &resolution{kind: r.kind()}
+	ctx = context.WithValue(ctx, resolutionKey{}, res)
+	next := func(ctx context.Context) (any, error) {
+		instance, b, err := r.resolve(ctx, sl)
+		res.built = b
+		return instance, err
+	}
+
+	typ := fmt.Sprint(typeKey)
+	for i := len(sl.opts.middlewares) - 1; i >= 0; i-- {
+		mw, inner := sl.opts.middlewares[i], next
+		next = func(ctx context.Context) (any, error) {
+			return mw(ctx, typ, inner)
+		}
+	}
+	instance, err = next(ctx)
+	if err == nil && !assignable(instance, keyType(typeKey)) {
+		return nil, res.built, fmt.Errorf("middleware returned %s for type %v", describe(instance), typeKey)
+	}
+	return instance, res.built, err
+}
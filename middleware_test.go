@@ -0,0 +1,143 @@
+package locator_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test a middleware can serve a cached value without running the provider
+func TestMiddlewareCache(t *testing.T) {
+	cache := make(map[string]any)
+	var events []locator.ResolveEvent
+	sl := locator.New(
+		locator.WithObserver(func(ev locator.ResolveEvent) {
+			events = append(events, ev)
+		}),
+		locator.WithMiddleware(func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+			if cached, ok := cache[typ]; ok {
+				return cached, nil
+			}
+			instance, err := next(ctx)
+			if err == nil {
+				cache[typ] = instance
+			}
+			return instance, err
+		}),
+	)
+
+	var callCount int
+	locator.RegisterFactory(sl, func() *TestService {
+		callCount++
+		return &TestService{Name: "Cached"}
+	})
+
+	first, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected the cached instance, got %p and %p", first, second)
+	}
+	if callCount != 1 {
+		t.Fatalf("expected provider to be called once, got %d", callCount)
+	}
+	if len(events) != 2 || events[0].CacheHit || !events[1].CacheHit {
+		t.Fatalf("expected a miss then a cache hit, got %+v", events)
+	}
+}
+
+// Test middlewares run in order with the first one outermost
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	trace := func(name string) locator.Middleware {
+		return func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+			order = append(order, name+" before")
+			instance, err := next(ctx)
+			order = append(order, name+" after")
+			return instance, err
+		}
+	}
+	sl := locator.New(locator.WithMiddleware(trace("outer"), trace("inner")))
+
+	locator.RegisterFactory(sl, func() *TestService {
+		order = append(order, "provider")
+		return &TestService{}
+	})
+	if _, err := locator.Get[*TestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"outer before", "inner before", "provider", "inner after", "outer after"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
+
+// Test a middleware returning a value of the wrong type fails the resolution
+func TestMiddlewareWrongType(t *testing.T) {
+	sl := locator.New(locator.WithMiddleware(func(context.Context, string, func(context.Context) (any, error)) (any, error) {
+		return "oops", nil
+	}))
+	locator.RegisterFactory(sl, func() *TestService { return &TestService{} })
+	locator.RegisterFactory(sl, func() int { return 1 })
+
+	_, err := locator.Get[*TestService](sl)
+	if err == nil || !strings.Contains(err.Error(), "middleware returned string for type *locator_test.TestService") {
+		t.Fatalf("expected a type error, got %v", err)
+	}
+	if _, err := locator.Get[int](sl); err == nil {
+		t.Fatalf("expected a type error, got %v", err)
+	}
+}
+
+// Test ProviderRan tells provider runs apart from cache hits
+func TestProviderRan(t *testing.T) {
+	var ran []bool
+	sl := locator.New(locator.WithMiddleware(func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+		instance, err := next(ctx)
+		ran = append(ran, locator.ProviderRan(ctx))
+		return instance, err
+	}))
+	locator.RegisterCachedFactory(sl, func() *TestService { return &TestService{} }, time.Hour)
+
+	locator.Get[*TestService](sl)
+	locator.Get[*TestService](sl)
+	if !reflect.DeepEqual(ran, []bool{true, false}) {
+		t.Fatalf("expected a provider run then a cache hit, got %v", ran)
+	}
+	if locator.ProviderRan(context.Background()) {
+		t.Fatalf("expected false outside a middleware")
+	}
+}
+
+// Test ResolverKind reports the lifetime of the registration being resolved
+func TestResolverKind(t *testing.T) {
+	var kinds []locator.Kind
+	sl := locator.New(locator.WithMiddleware(func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+		if kind, ok := locator.ResolverKind(ctx); ok {
+			kinds = append(kinds, kind)
+		}
+		return next(ctx)
+	}))
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.RegisterFactory(sl, func() *AnotherTestService { return &AnotherTestService{} })
+
+	locator.Get[*TestService](sl)
+	locator.Get[*AnotherTestService](sl)
+	expected := []locator.Kind{locator.KindLazySingleton, locator.KindFactory}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Fatalf("expected %v, got %v", expected, kinds)
+	}
+	if _, ok := locator.ResolverKind(context.Background()); ok {
+		t.Fatalf("expected no kind outside a middleware")
+	}
+}
@@ -0,0 +1,23 @@
+package locator
+
+import "fmt"
+
+// providerRun is a provider invoked for typeKey by the locator owning registry
+type providerRun struct {
+	registry *registry
+	typeKey  any
+}
+
+// providerRuns tracks the providers each goroutine runs under WithLocatorCheck,
+// innermost on top
+var providerRuns goroutineStack[providerRun]
+
+// checkLocator reports to the WithLocatorCheck callback a resolution of typeKey
+// from sl made by a provider that was invoked for another locator
+func (sl *ServiceLocator) checkLocator(typeKey any) {
+	run, ok := providerRuns.top()
+	if !ok || run.registry == sl.registry {
+		return
+	}
+	sl.opts.locatorCheck(fmt.Errorf("%w: provider for %v resolved %v from another locator than the one it was invoked for", ErrLocatorMismatch, run.typeKey, typeKey))
+}
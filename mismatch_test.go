@@ -0,0 +1,52 @@
+package locator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test WithLocatorCheck warns when a scoped provider resolves from the parent it
+// captured instead of the scope it is handed
+func TestLocatorCheck(t *testing.T) {
+	var warnings []error
+	sl := locator.New(locator.WithLocatorCheck(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	root := &TestService{Name: "Root"}
+	locator.RegisterSingleton(sl, root)
+	var resolved *TestService
+	locator.RegisterScoped(sl, func() *AnotherTestService {
+		resolved = locator.MustGet[*TestService](sl)
+		return &AnotherTestService{}
+	})
+	locator.RegisterScopedWithLocator(sl, func(scope *locator.ServiceLocator) *UnitOfWork {
+		locator.MustGet[*TestService](scope)
+		return &UnitOfWork{}
+	})
+
+	scope := sl.Scope()
+	locator.RegisterSingleton(scope, &TestService{Name: "Scope"})
+	if _, err := locator.Get[*UnitOfWork](scope); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warning for the handed scope, got %v", warnings)
+	}
+
+	// The captured parent hands out its own TestService instead of the scope's
+	if _, err := locator.Get[*AnotherTestService](scope); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolved != root {
+		t.Fatalf("expected the resolution to proceed, got %v", resolved)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], locator.ErrLocatorMismatch) {
+		t.Fatalf("expected one %v warning, got %v", locator.ErrLocatorMismatch, warnings)
+	}
+	if !strings.Contains(warnings[0].Error(), "provider for *locator_test.AnotherTestService resolved *locator_test.TestService") {
+		t.Fatalf("expected the warning to name both types, got %v", warnings[0])
+	}
+}
@@ -0,0 +1,29 @@
+package locator
+
+import "fmt"
+
+// Module packages a related set of registrations, such as everything a database
+// layer needs, so wiring can be composed from independent parts with Use
+type Module interface {
+	Register(sl *ServiceLocator) error
+}
+
+// ModuleFunc adapts a registration function to a Module
+type ModuleFunc func(sl *ServiceLocator) error
+
+// Register calls f(sl)
+func (f ModuleFunc) Register(sl *ServiceLocator) error {
+	return f(sl)
+}
+
+// Use registers modules in order, stopping at the first one that fails. A module
+// may itself call Use to compose other modules. Registrations made by modules
+// before the failure are kept
+func (sl *ServiceLocator) Use(modules ...Module) error {
+	for _, module := range modules {
+		if err := module.Register(sl); err != nil {
+			return fmt.Errorf("module %T: %w", module, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package locator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// databaseModule registers a TestService
+type databaseModule struct{}
+
+func (databaseModule) Register(sl *locator.ServiceLocator) error {
+	locator.RegisterSingleton(sl, &TestService{})
+	return nil
+}
+
+// Test Use applies modules in order, including nested ones
+func TestUse(t *testing.T) {
+	sl := locator.New()
+
+	var order []string
+	cache := locator.ModuleFunc(func(sl *locator.ServiceLocator) error {
+		order = append(order, "cache")
+		locator.RegisterSingleton(sl, &AnotherTestService{})
+		return nil
+	})
+	app := locator.ModuleFunc(func(sl *locator.ServiceLocator) error {
+		order = append(order, "app")
+		return sl.Use(databaseModule{}, cache)
+	})
+
+	if err := sl.Use(app); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(order) != 2 || order[0] != "app" || order[1] != "cache" {
+		t.Fatalf("expected modules app then cache, got %v", order)
+	}
+	if !locator.Has[*TestService](sl) || !locator.Has[*AnotherTestService](sl) {
+		t.Fatalf("expected both modules to register their services")
+	}
+}
+
+// Test Use stops at the first failing module and names it
+func TestUseError(t *testing.T) {
+	sl := locator.New()
+
+	errConfig := errors.New("missing DSN")
+	var laterCalled bool
+	err := sl.Use(
+		locator.ModuleFunc(func(sl *locator.ServiceLocator) error { return errConfig }),
+		locator.ModuleFunc(func(sl *locator.ServiceLocator) error {
+			laterCalled = true
+			return nil
+		}),
+	)
+	if !errors.Is(err, errConfig) {
+		t.Fatalf("expected %v, got %v", errConfig, err)
+	}
+	if err.Error() != "module locator.ModuleFunc: missing DSN" {
+		t.Fatalf("expected error naming the module, got %v", err)
+	}
+	if laterCalled {
+		t.Fatalf("expected later modules to be skipped")
+	}
+}
@@ -0,0 +1,92 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+)
+
+// RegisterSingletonNamed registers instance as the singleton of T under name, so
+// several instances of one type, such as a primary and a replica database, can be
+// registered side by side. Named registrations are keyed registrations with a
+// string key and can also be resolved with GetKeyed
+func RegisterSingletonNamed[T any](sl *ServiceLocator, name string, instance T) {
+	registerInstanceAt(sl, newKeyedKey[T](name), instance, false)
+}
+
+// RegisterLazySingletonNamed registers a provider function that will be used to
+// create the singleton of T under name on first access
+func RegisterLazySingletonNamed[T any](sl *ServiceLocator, name string, provider Provider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	typeKey := newKeyedKey[T](name)
+	sl.registerResolver(typeKey, &lazySingleton[T]{provider: provider.withLocator().withError(), key: typeKey}, false)
+}
+
+// RegisterFactoryNamed registers a provider function under name that will create a
+// new instance each time GetNamed is called
+func RegisterFactoryNamed[T any](sl *ServiceLocator, name string, provider Provider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	sl.registerResolver(newKeyedKey[T](name), provider, false)
+}
+
+// GetNamed retrieves the instance of the requested type registered under name
+func GetNamed[T any](sl *ServiceLocator, name string) (T, error) {
+	return GetKeyed[string, T](sl, name)
+}
+
+// HasNamed reports whether T is registered under name, without running any provider
+func HasNamed[T any](sl *ServiceLocator, name string) bool {
+	return sl.hasInherited(newKeyedKey[T](name))
+}
+
+// RegisterAnyNamed registers instance under name without a static type, for
+// plugin hosts that only learn service names at runtime, for example from a
+// configuration file. It is resolved with GetAny
+func RegisterAnyNamed(sl *ServiceLocator, name string, instance any) {
+	RegisterSingletonNamed[any](sl, name, instance)
+}
+
+// GetAny retrieves the service registered under name without knowing its type.
+// A registration made with RegisterAnyNamed is returned first; otherwise name is
+// looked up among the named registrations of every type, including those a scope
+// inherits, and GetAny fails if several types are registered under it
+func (sl *ServiceLocator) GetAny(name string) (any, error) {
+	if instance, err := GetNamed[any](sl, name); err == nil || HasNamed[any](sl, name) {
+		return instance, err
+	}
+
+	var matches []any
+	for p := sl; p != nil && len(matches) == 0; p = p.parent {
+		view := p.view()
+		for typeKey := range view.instances {
+			if k, ok := typeKey.(keyedKey); ok && k.key == name {
+				matches = append(matches, typeKey)
+			}
+		}
+		for typeKey := range view.providers {
+			if _, exists := view.instances[typeKey]; exists {
+				continue
+			}
+			if k, ok := typeKey.(keyedKey); ok && k.key == name {
+				matches = append(matches, typeKey)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no service registered under name %q", name)
+	case 1:
+		instance, found, err := sl.resolve(context.Background(), matches[0])
+		if !found {
+			err = notRegistered(matches[0])
+		}
+		return instance, err
+	default:
+		sortByName(matches)
+		return nil, fmt.Errorf("name %q is registered for several types: %v", name, matches)
+	}
+}
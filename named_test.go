@@ -0,0 +1,95 @@
+package locator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test named registrations of the same type resolve independently
+func TestNamed(t *testing.T) {
+	sl := locator.New()
+
+	primary := &Config{Debug: false}
+	locator.RegisterSingletonNamed(sl, "primary", primary)
+	var callCount int
+	locator.RegisterLazySingletonNamed(sl, "replica", func() *Config {
+		callCount++
+		return &Config{Debug: true}
+	})
+	locator.RegisterFactoryNamed(sl, "scratch", func() *Config {
+		return &Config{}
+	})
+
+	config, err := locator.GetNamed[*Config](sl, "primary")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config != primary {
+		t.Fatalf("expected the primary instance, got %v", config)
+	}
+
+	first, err := locator.GetNamed[*Config](sl, "replica")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, _ := locator.GetNamed[*Config](sl, "replica")
+	if !first.Debug || first != second || callCount != 1 {
+		t.Fatalf("expected one shared replica instance, got %p and %p after %d calls", first, second, callCount)
+	}
+
+	scratch1, _ := locator.GetNamed[*Config](sl, "scratch")
+	scratch2, _ := locator.GetNamed[*Config](sl, "scratch")
+	if scratch1 == scratch2 {
+		t.Fatalf("expected distinct factory instances")
+	}
+
+	if _, err := locator.GetNamed[*Config](sl, "missing"); err == nil {
+		t.Fatalf("expected error for an unknown name, got nil")
+	}
+	if _, err := locator.Get[*Config](sl); err == nil {
+		t.Fatalf("expected error for the unnamed type, got nil")
+	}
+}
+
+// Test HasNamed reports named registrations
+func TestHasNamed(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingletonNamed(sl, "primary", &Config{})
+
+	if !locator.HasNamed[*Config](sl, "primary") {
+		t.Fatalf("expected true for a registered name")
+	}
+	if locator.HasNamed[*Config](sl, "replica") || locator.Has[*Config](sl) {
+		t.Fatalf("expected false for an unregistered name and the unnamed type")
+	}
+}
+
+// Test GetAny resolves services by name alone
+func TestGetAny(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterAnyNamed(sl, "greeter", baseGreeter{})
+	locator.RegisterLazySingletonNamed(sl, "primary", func() *TestService { return &TestService{Name: "primary"} })
+	locator.RegisterSingletonNamed(sl, "shared", &TestService{})
+	locator.RegisterSingletonNamed(sl, "shared", &AnotherTestService{})
+
+	greeter, err := sl.GetAny("greeter")
+	if g, ok := greeter.(Greeter); err != nil || !ok || g.Greet() != "hello" {
+		t.Fatalf("expected the greeter, got %v, %v", greeter, err)
+	}
+
+	scope := sl.Scope()
+	primary, err := scope.GetAny("primary")
+	if s, ok := primary.(*TestService); err != nil || !ok || s.Name != "primary" {
+		t.Fatalf("expected the primary service, got %v, %v", primary, err)
+	}
+
+	if _, err := sl.GetAny("shared"); err == nil || !strings.Contains(err.Error(), `name "shared" is registered for several types`) {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+	if _, err := sl.GetAny("missing"); err == nil || err.Error() != `no service registered under name "missing"` {
+		t.Fatalf("expected missing error, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package locator
+
+import "time"
+
+// Kind describes the lifetime of a registration
+type Kind int
+
+const (
+	// KindUnregistered is reported when no registration exists for the type
+	KindUnregistered Kind = iota
+	// KindSingleton is an instance registered up front
+	KindSingleton
+	// KindLazySingleton is a singleton built by its provider on first access
+	KindLazySingleton
+	// KindFactory builds a new instance on every resolution
+	KindFactory
+	// KindCachedFactory rebuilds its instance when the cached one expires
+	KindCachedFactory
+	// KindScoped is built once per scope
+	KindScoped
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindSingleton:
+		return "singleton"
+	case KindLazySingleton:
+		return "lazy"
+	case KindFactory:
+		return "factory"
+	case KindCachedFactory:
+		return "cached"
+	case KindScoped:
+		return "scoped"
+	default:
+		return "unregistered"
+	}
+}
+
+// ResolveEvent describes a single resolution reported to an observer
+type ResolveEvent struct {
+	// Type is the name of the resolved type
+	Type string
+	// Kind is the lifetime of the registration that served the resolution
+	Kind Kind
+	// CacheHit reports whether an existing instance was returned without running a provider
+	CacheHit bool
+	// Duration is the time spent running the provider, zero for cache hits
+	Duration time.Duration
+	// Err is the resolution error, if any
+	Err error
+}
@@ -0,0 +1,76 @@
+package locator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test the observer receives one event per resolution
+func TestObserver(t *testing.T) {
+	var events []locator.ResolveEvent
+	var sl *locator.ServiceLocator
+	sl = locator.New(locator.WithObserver(func(ev locator.ResolveEvent) {
+		events = append(events, ev)
+		// Resolving from inside the observer must not deadlock
+		if ev.Type == "*locator_test.AnotherTestService" {
+			if _, err := locator.Get[int](sl); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}
+	}))
+
+	locator.RegisterSingleton(sl, 42)
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		time.Sleep(5 * time.Millisecond)
+		return &TestService{Name: "Lazy"}
+	})
+	locator.RegisterFactory(sl, func() *AnotherTestService {
+		return &AnotherTestService{ID: 1}
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := locator.Get[*TestService](sl); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if _, err := locator.Get[*AnotherTestService](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := locator.Get[string](sl); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	first := events[0]
+	if first.Type != "*locator_test.TestService" || first.Kind != locator.KindLazySingleton || first.CacheHit {
+		t.Fatalf("expected a lazy construction event, got %+v", first)
+	}
+	if first.Duration < 5*time.Millisecond {
+		t.Fatalf("expected the construction to be timed, got %v", first.Duration)
+	}
+
+	second := events[1]
+	if second.Kind != locator.KindLazySingleton || !second.CacheHit || second.Duration != 0 {
+		t.Fatalf("expected a lazy cache hit event, got %+v", second)
+	}
+
+	factory := events[2]
+	if factory.Kind != locator.KindFactory || factory.CacheHit {
+		t.Fatalf("expected a factory event, got %+v", factory)
+	}
+
+	nested := events[3]
+	if nested.Type != "int" || nested.Kind != locator.KindSingleton || !nested.CacheHit {
+		t.Fatalf("expected a singleton event from the nested resolution, got %+v", nested)
+	}
+
+	missing := events[4]
+	if missing.Type != "string" || missing.Kind != locator.KindUnregistered || missing.Err == nil {
+		t.Fatalf("expected an unregistered event, got %+v", missing)
+	}
+}
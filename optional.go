@@ -0,0 +1,76 @@
+package locator
+
+import (
+	"context"
+	"reflect"
+)
+
+// Optional holds a dependency that may not be registered. As a parameter of a
+// function passed to Invoke or RegisterConstructor, or as a field filled by Fill
+// or Populate, it is resolved like T but leaves the holder empty instead of
+// failing when T is not registered, so an optional tracer can be used only if
+// present. A registered provider that fails is still reported as an error
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Get returns the held instance and whether T was registered
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// elemType returns the reflect.Type of T
+func (Optional[T]) elemType() reflect.Type {
+	return getTypeKey[T]().(reflect.Type)
+}
+
+// set stores a resolved instance of T
+func (o *Optional[T]) set(instance any) {
+	o.value, o.ok = castInstance[T](instance), true
+}
+
+// optional is implemented by pointers to Optional, so it can be filled through
+// reflection without knowing T
+type optional interface {
+	elemType() reflect.Type
+	set(instance any)
+}
+
+// optionalType is the reflect.Type of the optional interface
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// optionalElem returns T if typ is an Optional[T]
+func optionalElem(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || !reflect.PointerTo(typ).Implements(optionalType) {
+		return nil, false
+	}
+	return reflect.Zero(typ).Interface().(interface{ elemType() reflect.Type }).elemType(), true
+}
+
+// newOptional returns an Optional of type typ holding value if found is true, or
+// an empty one otherwise
+func newOptional(typ reflect.Type, value reflect.Value, found bool) reflect.Value {
+	holder := reflect.New(typ)
+	if found {
+		holder.Interface().(optional).set(value.Interface())
+	}
+	return holder.Elem()
+}
+
+// resolveOptional resolves the element of the Optional type typ under typeKey
+func (sl *ServiceLocator) resolveOptional(ctx context.Context, typeKey any, typ reflect.Type) (reflect.Value, error) {
+	elem, _ := optionalElem(typ)
+	value, found, err := sl.resolveKeyValue(ctx, typeKey, elem)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return newOptional(typ, value, found), nil
+}
+
+// GetOptional retrieves an instance of the requested type if it is registered,
+// reporting false otherwise. Like TryGet it also reports false when the provider
+// fails; use Get where that failure must be told apart from an absent service
+func GetOptional[T any](sl *ServiceLocator) (T, bool) {
+	return TryGet[T](sl)
+}
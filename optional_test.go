@@ -0,0 +1,60 @@
+package locator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test GetOptional reports whether the type is registered
+func TestGetOptional(t *testing.T) {
+	sl := locator.New()
+
+	if _, ok := locator.GetOptional[*TestService](sl); ok {
+		t.Fatalf("expected false for an unregistered type")
+	}
+	service := &TestService{Name: "Service"}
+	locator.RegisterSingleton(sl, service)
+	if got, ok := locator.GetOptional[*TestService](sl); !ok || got != service {
+		t.Fatalf("expected the registered service, got %v, %v", got, ok)
+	}
+}
+
+type TracedHandler struct {
+	Service *TestService
+	Tracer  locator.Optional[Greeter]
+}
+
+// Test Optional parameters and fields are filled when registered and left empty
+// otherwise
+func TestOptional(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+
+	var handler TracedHandler
+	if err := locator.Fill(sl, &handler); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := handler.Tracer.Get(); ok {
+		t.Fatalf("expected an empty optional")
+	}
+
+	locator.RegisterAs[Greeter](sl, baseGreeter{})
+	err := sl.Invoke(func(tracer locator.Optional[Greeter]) {
+		greeter, ok := tracer.Get()
+		if !ok || greeter.Greet() != "hello" {
+			t.Fatalf("expected the registered greeter, got %v, %v", greeter, ok)
+		}
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	errBroken := errors.New("broken")
+	locator.RegisterFactoryE(sl, func() (*AnotherTestService, error) { return nil, errBroken })
+	err = sl.Invoke(func(locator.Optional[*AnotherTestService]) {})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("expected %v, got %v", errBroken, err)
+	}
+}
@@ -0,0 +1,161 @@
+package locator
+
+import (
+	"io"
+	"time"
+)
+
+// Option configures a ServiceLocator created by New
+type Option func(*options)
+
+// options holds the configuration of a ServiceLocator
+type options struct {
+	observer             func(ResolveEvent)
+	autoStruct           bool
+	platform             string
+	middlewares          []Middleware
+	goroutineLabels      bool
+	pointerValueFallback bool
+	constructTimeout     time.Duration
+	strictRegistration   bool
+	tracer               *tracer
+	logger               logger
+	strictOverwrite      bool
+	panicRecovery        bool
+	lazyRetry            RetryPolicy
+	scopeGuard           bool
+	locatorCheck         func(error)
+}
+
+// WithObserver installs a callback receiving one ResolveEvent per resolution.
+// The callback is invoked after the locator has released its locks, so it may
+// safely resolve from the locator itself
+func WithObserver(observer func(ev ResolveEvent)) Option {
+	return func(o *options) {
+		o.observer = observer
+	}
+}
+
+// WithAutoStructConstruction makes Get synthesize unregistered pointer-to-struct
+// types. The struct is allocated and each exported field is resolved from the
+// locator by its type, synthesizing unregistered struct pointer fields in turn;
+// resolution fails if any field cannot be resolved. A synthesized struct behaves
+// like a factory and is built on every Get
+func WithAutoStructConstruction() Option {
+	return func(o *options) {
+		o.autoStruct = true
+	}
+}
+
+// WithPlatform sets the platform used to select RegisterPlatform implementations
+// instead of runtime.GOOS
+func WithPlatform(platform string) Option {
+	return func(o *options) {
+		o.platform = platform
+	}
+}
+
+// WithMiddleware adds middlewares around every provider invocation. Middlewares
+// run in the order they are given, the first one being the outermost
+func WithMiddleware(mws ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, mws...)
+	}
+}
+
+// WithGoroutineLabels makes the locator record where each lazy singleton was first
+// built: the pprof labels of the resolving context and the calling function. The
+// record is available from Info
+func WithGoroutineLabels() Option {
+	return func(o *options) {
+		o.goroutineLabels = true
+	}
+}
+
+// WithPointerValueFallback makes Get resolve *T from a registered T value by
+// returning the address of a copy, and T from a registered *T by dereferencing it,
+// when the requested type itself is not registered
+func WithPointerValueFallback() Option {
+	return func(o *options) {
+		o.pointerValueFallback = true
+	}
+}
+
+// WithConstructTimeout limits how long Get waits for a lazy singleton to be built.
+// Callers waiting longer than d get an error, while the construction keeps running
+// in the background; if it succeeds its instance is kept and returned by later
+// calls, and if it fails the next Get starts a new construction
+func WithConstructTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.constructTimeout = d
+	}
+}
+
+// WithStrictRegistration makes registering a nil provider panic instead of being
+// skipped, so the mistake surfaces at startup rather than on the first Get
+func WithStrictRegistration() Option {
+	return func(o *options) {
+		o.strictRegistration = true
+	}
+}
+
+// WithStrictOverwrite makes registering a type that is already registered panic
+// with an error wrapping ErrDuplicateRegistration and naming both call sites,
+// instead of silently replacing the registration. Registration functions that
+// report errors return it instead. Override still replaces registrations
+func WithStrictOverwrite() Option {
+	return func(o *options) {
+		o.strictOverwrite = true
+	}
+}
+
+// WithTracing makes the locator write an indented tree of every resolution to w,
+// once the outermost resolution completes. Each line names the resolved type, its
+// lifetime, where it was registered and how long it took, nested under the
+// resolution whose provider requested it
+func WithTracing(w io.Writer) Option {
+	return func(o *options) {
+		o.tracer = &tracer{w: w}
+	}
+}
+
+// WithPanicRecovery makes Get recover a panic in any provider or middleware and
+// return it as an error carrying the panic value and the stack of the panic,
+// instead of letting it escape into the caller. Lazy singletons recover panics
+// even without it, and a lazy singleton whose provider panicked is built again by
+// the next Get
+func WithPanicRecovery() Option {
+	return func(o *options) {
+		o.panicRecovery = true
+	}
+}
+
+// WithScopeGuard makes resolving a scoped type fail with an error wrapping
+// ErrForeignScope when it happens while a scoped instance is built for another
+// scope, as when a provider captured the scope of a different request instead of
+// using the locator it is handed. It is meant for tests and debugging, since it
+// tracks the scopes being built for each goroutine
+func WithScopeGuard() Option {
+	return func(o *options) {
+		o.scopeGuard = true
+	}
+}
+
+// WithLocatorCheck makes the locator call warn with an error wrapping
+// ErrLocatorMismatch whenever a provider resolves a dependency from another
+// locator than the one it was invoked for, typically a closure that captured the
+// parent instead of using the scope it is handed. The resolution itself proceeds.
+// Only resolutions made on the goroutine running the provider are checked
+func WithLocatorCheck(warn func(err error)) Option {
+	return func(o *options) {
+		o.locatorCheck = warn
+	}
+}
+
+// WithLazyRetry sets how lazy singletons whose provider failed or panicked are
+// built again. By default every later Get retries, as with RetryAlways
+func WithLazyRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.lazyRetry = policy
+	}
+}
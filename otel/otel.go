@@ -0,0 +1,51 @@
+// Package locatorotel creates OpenTelemetry spans around the providers run by a
+// locator, so expensive construction shows up in distributed traces
+package locatorotel
+
+import (
+	"context"
+
+	"github.com/RobinHood3082/locator"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by this package
+const instrumentationName = "github.com/RobinHood3082/locator/otel"
+
+// Middleware returns a locator middleware that creates a span for every resolution
+// reaching the middlewares. The span is named "locator.construct <type>" when a
+// provider runs and "locator.lookup <type>" when an existing instance is returned
+// instead, as for cached factory hits, aliases and platform or versioned
+// registrations. The span carries the type as locator.type and the lifetime of
+// the registration as locator.kind. The span is a child of the span in the resolution context, so it
+// is linked to the caller when GetCtx is used, and providers receiving a context
+// see it as their parent. A nil tp uses the global tracer provider
+func Middleware(tp trace.TracerProvider) locator.Middleware {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	tracer := tp.Tracer(instrumentationName)
+
+	return func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+		attrs := []attribute.KeyValue{attribute.String("locator.type", typ)}
+		if kind, ok := locator.ResolverKind(ctx); ok {
+			attrs = append(attrs, attribute.String("locator.kind", kind.String()))
+		}
+		ctx, span := tracer.Start(ctx, "locator.construct "+typ, trace.WithAttributes(attrs...))
+		defer span.End()
+
+		instance, err := next(ctx)
+		// Whether a provider runs is only known once next returns
+		if !locator.ProviderRan(ctx) {
+			span.SetName("locator.lookup " + typ)
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return instance, err
+	}
+}
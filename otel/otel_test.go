@@ -0,0 +1,136 @@
+package locatorotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+	locatorotel "github.com/RobinHood3082/locator/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type Database struct{}
+
+type Repository struct{}
+
+// Test a span is created for each provider run, linked to the caller's span
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sl := locator.New(locator.WithMiddleware(locatorotel.Middleware(tp)))
+
+	locator.RegisterLazySingleton(sl, func() *Database { return &Database{} })
+	locator.RegisterFactoryCtx(sl, func(ctx context.Context) (*Repository, error) {
+		if _, err := locator.GetCtx[*Database](ctx, sl); err != nil {
+			return nil, err
+		}
+		return &Repository{}, nil
+	})
+
+	ctx, root := tp.Tracer("test").Start(context.Background(), "request")
+	if _, err := locator.GetCtx[*Repository](ctx, sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// The singleton is built, so only the factory runs again
+	if _, err := locator.GetCtx[*Repository](ctx, sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	root.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+	database, repository := spans[0], spans[1]
+	if database.Name() != "locator.construct *locatorotel_test.Database" {
+		t.Fatalf("expected the database span first, got %v", database.Name())
+	}
+	if repository.Name() != "locator.construct *locatorotel_test.Repository" {
+		t.Fatalf("expected the repository span second, got %v", repository.Name())
+	}
+	if database.Parent().SpanID() != repository.SpanContext().SpanID() {
+		t.Fatalf("expected the database span to be a child of the repository span")
+	}
+	if repository.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("expected the repository span to be a child of the caller's span")
+	}
+}
+
+// Test provider errors are recorded on the span
+func TestMiddlewareError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sl := locator.New(locator.WithMiddleware(locatorotel.Middleware(tp)))
+
+	locator.RegisterFactoryE(sl, func() (*Database, error) {
+		return nil, errors.New("connection refused")
+	})
+	if _, err := locator.Get[*Database](sl); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if status := spans[0].Status(); status.Code != codes.Error || status.Description != "connection refused" {
+		t.Fatalf("expected error status, got %v", status)
+	}
+}
+
+// Test resolutions that run no provider get a lookup span
+func TestMiddlewareLookup(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sl := locator.New(locator.WithMiddleware(locatorotel.Middleware(tp)))
+
+	locator.RegisterCachedFactory(sl, func() *Database { return &Database{} }, time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := locator.Get[*Database](sl); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "locator.construct *locatorotel_test.Database" {
+		t.Fatalf("expected a construct span for the first resolution, got %v", spans[0].Name())
+	}
+	if spans[1].Name() != "locator.lookup *locatorotel_test.Database" {
+		t.Fatalf("expected a lookup span for the cache hit, got %v", spans[1].Name())
+	}
+}
+
+// Test constructions triggered by Warmup get a span with the type and kind
+func TestMiddlewareWarmup(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sl := locator.New(locator.WithMiddleware(locatorotel.Middleware(tp)))
+
+	locator.RegisterLazySingleton(sl, func() *Database { return &Database{} })
+	if _, err := sl.Warmup(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "locator.construct *locatorotel_test.Database" {
+		t.Fatalf("expected a construct span, got %v", spans[0].Name())
+	}
+	attrs := make(map[attribute.Key]string)
+	for _, attr := range spans[0].Attributes() {
+		attrs[attr.Key] = attr.Value.AsString()
+	}
+	if attrs["locator.type"] != "*locatorotel_test.Database" || attrs["locator.kind"] != locator.KindLazySingleton.String() {
+		t.Fatalf("expected the type and kind attributes, got %v", attrs)
+	}
+}
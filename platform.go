@@ -0,0 +1,114 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// DefaultPlatform is the platform name used when no registration matches the
+// current platform
+const DefaultPlatform = "default"
+
+// RegisterPlatform registers instance as the implementation of T for platform.
+// Several platforms can be registered side by side; GetPlatform and Get pick the
+// one matching the locator's platform, falling back to DefaultPlatform
+func RegisterPlatform[T any](sl *ServiceLocator, platform string, instance T) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	typeKey := getTypeKey[T]()
+	sl.mustNotBeFrozen(typeKey)
+
+	// Copy on write so resolutions in flight keep a consistent view
+	instances := make(map[string]T)
+	pp, ok := sl.providers[typeKey].(*platformProvider[T])
+	if ok {
+		for name, existing := range pp.instances {
+			instances[name] = existing
+		}
+	}
+	// Adding another platform is not an overwrite, replacing one or another kind
+	// of registration is
+	_, replaced := instances[platform]
+	sl.mustAdmit(typeKey, KindSingleton, replaced || (!ok && sl.isRegistered(typeKey)))
+	delete(sl.instances, typeKey)
+
+	instances[platform] = instance
+	sl.providers[typeKey] = &platformProvider[T]{instances: instances}
+	sl.changed()
+}
+
+// GetPlatform retrieves the implementation of T registered for the locator's
+// platform, which is runtime.GOOS unless configured with WithPlatform, falling
+// back to the DefaultPlatform registration. It resolves like Get, so a scope or
+// child locator finds the registrations of its ancestors, but fails if the
+// nearest registration of T is not a platform registration
+func GetPlatform[T any](sl *ServiceLocator) (T, error) {
+	var zero T
+	typeKey := getTypeKey[T]()
+	if _, ok := sl.inheritedProvider(typeKey).(*platformProvider[T]); !ok {
+		return zero, fmt.Errorf("no platform provider registered for type %v", typeKey)
+	}
+
+	instance, found, err := sl.resolve(context.Background(), typeKey)
+	if !found {
+		return zero, sl.missingError(typeKey)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return castInstance[T](instance), nil
+}
+
+// platform returns the platform used to select platform registrations
+func (sl *ServiceLocator) platform() string {
+	if sl.opts.platform != "" {
+		return sl.opts.platform
+	}
+	return runtime.GOOS
+}
+
+// platformProvider holds the implementations of a service for each platform
+type platformProvider[T any] struct {
+	instances map[string]T
+	// decorated caches the decorated implementation by requested platform
+	decorated sync.Map
+}
+
+// resolve returns the decorated implementation for the locator's platform
+func (pp *platformProvider[T]) resolve(_ context.Context, sl *ServiceLocator) (any, bool, error) {
+	platform := sl.platform()
+	if instance, ok := pp.decorated.Load(platform); ok {
+		return instance, false, nil
+	}
+	instance, err := pp.getInstance(platform)
+	if err != nil {
+		return nil, false, err
+	}
+	decorated, _ := pp.decorated.LoadOrStore(platform, decorate(sl, instance))
+	return decorated, false, nil
+}
+
+// withoutDecorated returns a copy of pp with an empty cache
+func (pp *platformProvider[T]) withoutDecorated() any {
+	return &platformProvider[T]{instances: pp.instances}
+}
+
+// kind reports that pp holds prebuilt singletons
+func (pp *platformProvider[T]) kind() Kind {
+	return KindSingleton
+}
+
+// getInstance returns the implementation for platform or the default one
+func (pp *platformProvider[T]) getInstance(platform string) (T, error) {
+	if instance, exists := pp.instances[platform]; exists {
+		return instance, nil
+	}
+	if instance, exists := pp.instances[DefaultPlatform]; exists {
+		return instance, nil
+	}
+
+	var zero T
+	return zero, fmt.Errorf("no implementation of type %v registered for platform %q", getTypeKey[T](), platform)
+}
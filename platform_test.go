@@ -0,0 +1,113 @@
+package locator_test
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// registerPlatforms registers a linux and a default implementation
+func registerPlatforms(sl *locator.ServiceLocator) {
+	locator.RegisterPlatform(sl, "linux", &TestService{Name: "Linux"})
+	locator.RegisterPlatform(sl, locator.DefaultPlatform, &TestService{Name: "Default"})
+}
+
+// Test platform selection with a configured platform key
+func TestGetPlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		expected string
+	}{
+		{"linux", "Linux"},
+		{"windows", "Default"},
+	}
+	for _, tt := range tests {
+		sl := locator.New(locator.WithPlatform(tt.platform))
+		registerPlatforms(sl)
+
+		service, err := locator.GetPlatform[*TestService](sl)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.platform, err)
+		}
+		if service.Name != tt.expected {
+			t.Fatalf("%s: expected %s, got %s", tt.platform, tt.expected, service.Name)
+		}
+
+		// Get resolves platform registrations the same way
+		service, err = locator.Get[*TestService](sl)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.platform, err)
+		}
+		if service.Name != tt.expected {
+			t.Fatalf("%s: expected %s from Get, got %s", tt.platform, tt.expected, service.Name)
+		}
+	}
+}
+
+// Test platform selection defaults to runtime.GOOS
+func TestGetPlatformRuntime(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterPlatform(sl, runtime.GOOS, &TestService{Name: "Current"})
+	service, err := locator.GetPlatform[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Current" {
+		t.Fatalf("expected Current, got %s", service.Name)
+	}
+
+	locator.RegisterPlatform(sl, "plan9-only", &AnotherTestService{})
+	if runtime.GOOS != "plan9-only" {
+		if _, err := locator.GetPlatform[*AnotherTestService](sl); err == nil {
+			t.Fatalf("expected error without a matching or default registration, got nil")
+		}
+	}
+}
+
+// Test GetPlatform resolves like Get: through child locators, with hooks, and
+// decorating each implementation once
+func TestGetPlatformResolution(t *testing.T) {
+	sl := locator.New(locator.WithPlatform("linux"))
+	registerPlatforms(sl)
+	var decorations int
+	locator.Decorate(sl, func(s *TestService) *TestService {
+		decorations++
+		return &TestService{Name: s.Name + "+"}
+	})
+	var resolutions int
+	sl.OnResolve(func(reflect.Type, any, time.Duration, error) { resolutions++ })
+
+	child := locator.NewChild(sl)
+	for i := 0; i < 2; i++ {
+		service, err := locator.GetPlatform[*TestService](child)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if service.Name != "Linux+" {
+			t.Fatalf("expected Linux+, got %s", service.Name)
+		}
+	}
+	if decorations != 1 {
+		t.Fatalf("expected a single decoration, got %d", decorations)
+	}
+	if resolutions != 2 {
+		t.Fatalf("expected the hook to see 2 resolutions, got %d", resolutions)
+	}
+
+	// A later decorator applies to the next resolution
+	locator.Decorate(sl, func(s *TestService) *TestService {
+		return &TestService{Name: s.Name + "!"}
+	})
+	if service, err := locator.GetPlatform[*TestService](child); err != nil || service.Name != "Linux+!" {
+		t.Fatalf("expected Linux+!, got %v, %v", service, err)
+	}
+
+	locator.RegisterSingleton(child, &TestService{Name: "Child"})
+	if _, err := locator.GetPlatform[*TestService](child); err == nil {
+		t.Fatalf("expected an error for a non platform registration, got nil")
+	}
+}
@@ -0,0 +1,59 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// pointerValueResolver resolves typ from source, its registered value or pointer
+// counterpart
+type pointerValueResolver struct {
+	typ    reflect.Type
+	source reflect.Type
+}
+
+// resolve takes the address of a copy of a registered value, or dereferences a
+// registered pointer
+func (pv pointerValueResolver) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	instance, found, err := sl.resolve(ctx, pv.source)
+	if !found {
+		err = notRegistered(pv.source)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+
+	v := reflect.ValueOf(instance)
+	if pv.typ.Kind() == reflect.Pointer && pv.typ.Elem() == pv.source {
+		ptr := reflect.New(pv.source)
+		if v.IsValid() {
+			ptr.Elem().Set(v)
+		}
+		return ptr.Interface(), true, nil
+	}
+	if !v.IsValid() || v.IsNil() {
+		return nil, true, fmt.Errorf("cannot resolve %v: registered %v is nil", pv.typ, pv.source)
+	}
+	return v.Elem().Interface(), true, nil
+}
+
+// kind reports that every resolution produces a new copy, like a factory
+func (pv pointerValueResolver) kind() Kind {
+	return KindFactory
+}
+
+// pointerValueCounterpart returns the registered value type of a pointer type, or
+// the registered pointer type of a value type. The caller must not hold sl.mu
+func (sl *ServiceLocator) pointerValueCounterpart(typ reflect.Type) (reflect.Type, bool) {
+	if typ == nil {
+		return nil, false
+	}
+	if typ.Kind() == reflect.Pointer && sl.hasRegistration(typ.Elem()) {
+		return typ.Elem(), true
+	}
+	if ptr := reflect.PointerTo(typ); sl.hasRegistration(ptr) {
+		return ptr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package locator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test the pointer value fallback resolves both directions
+func TestPointerValueFallback(t *testing.T) {
+	sl := locator.New(locator.WithPointerValueFallback())
+	locator.RegisterSingleton(sl, TestService{Name: "Value"})
+	locator.RegisterSingleton(sl, &AnotherTestService{ID: 7})
+
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Value" {
+		t.Fatalf("expected Value, got %s", service.Name)
+	}
+
+	// The pointer refers to a copy, so the registered value is not modified
+	service.Name = "Modified"
+	value, err := locator.Get[TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value.Name != "Value" {
+		t.Fatalf("expected Value, got %s", value.Name)
+	}
+
+	another, err := locator.Get[AnotherTestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if another.ID != 7 {
+		t.Fatalf("expected 7, got %d", another.ID)
+	}
+}
+
+// Test a nil registered pointer cannot be dereferenced
+func TestPointerValueFallbackNil(t *testing.T) {
+	sl := locator.New(locator.WithPointerValueFallback())
+	locator.RegisterSingleton[*TestService](sl, nil)
+
+	if _, err := locator.Get[TestService](sl); err == nil || !strings.Contains(err.Error(), "is nil") {
+		t.Fatalf("expected nil pointer error, got %v", err)
+	}
+}
+
+// Test the error hints at a pointer value mismatch when the fallback is disabled
+func TestPointerValueMismatchHint(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, TestService{Name: "Value"})
+
+	_, err := locator.Get[*TestService](sl)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	for _, part := range []string{"no provider registered for type *locator_test.TestService", "locator_test.TestService is registered", "WithPointerValueFallback"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Fatalf("expected error containing %q, got %v", part, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// injectTag is the struct tag value marking a field for injection by Populate
+const injectTag = "inject"
+
+// skipTag is the struct tag value excluding a field from injection by Fill
+const skipTag = "-"
+
+// fieldTag holds the comma-separated options of a field's locator struct tag
+type fieldTag struct {
+	inject   bool
+	skip     bool
+	optional bool
+	name     string
+	named    bool
+}
+
+// parseFieldTag parses a locator struct tag such as "inject,name=replica" or
+// "optional"
+func parseFieldTag(tag string) (fieldTag, error) {
+	var parsed fieldTag
+	if tag == "" {
+		return parsed, nil
+	}
+	if tag == skipTag {
+		parsed.skip = true
+		return parsed, nil
+	}
+	for _, option := range strings.Split(tag, ",") {
+		switch name, value, hasValue := strings.Cut(strings.TrimSpace(option), "="); {
+		case name == injectTag && !hasValue:
+			parsed.inject = true
+		case name == "optional" && !hasValue:
+			parsed.optional = true
+		case name == "name" && hasValue && value != "":
+			parsed.name, parsed.named = value, true
+		default:
+			return parsed, fmt.Errorf("unknown locator tag option %q", option)
+		}
+	}
+	return parsed, nil
+}
+
+// Populate resolves the exported fields of the struct pointed to by target that
+// are tagged `locator:"inject"` and assigns them. Unexported and untagged fields
+// are left untouched. A field tagged `locator:"name=replica"` is resolved from the
+// registration under that name, and one tagged `locator:"optional"` is left
+// untouched if its type is not registered; either implies inject. Every field
+// that cannot be resolved is reported in the returned error
+func Populate(sl *ServiceLocator, target any) error {
+	return injectFields(sl, target, func(tag fieldTag) bool {
+		return tag.inject || tag.named || tag.optional
+	})
+}
+
+// Fill resolves every exported field of the struct pointed to by target and
+// assigns it, except fields tagged `locator:"-"`, so a handler struct can be wired
+// without tagging each dependency. Unexported fields are left untouched. The name
+// and optional tag options are honoured as for Populate. Every field that cannot
+// be resolved is reported in the returned error
+func Fill(sl *ServiceLocator, target any) error {
+	return injectFields(sl, target, func(tag fieldTag) bool {
+		return !tag.skip
+	})
+}
+
+// injectFields resolves and assigns the exported fields of the struct pointed to
+// by target for which inject returns true
+func injectFields(sl *ServiceLocator, target any, inject func(tag fieldTag) bool) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	v = v.Elem()
+	t := v.Type()
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, err := parseFieldTag(field.Tag.Get("locator"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+			continue
+		}
+		if !inject(tag) {
+			continue
+		}
+
+		typ := field.Type
+		elem, isOptional := optionalElem(typ)
+		if isOptional {
+			typ = elem
+		}
+		var typeKey any = typ
+		if tag.named {
+			typeKey = keyedKey{typ: typ, key: tag.name}
+		}
+		var value reflect.Value
+		found := true
+		if isOptional {
+			value, err = sl.resolveOptional(context.Background(), typeKey, field.Type)
+		} else {
+			value, found, err = sl.resolveKeyValue(context.Background(), typeKey, typ)
+		}
+		if !found {
+			if tag.optional {
+				continue
+			}
+			err = sl.missingError(typeKey)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+			continue
+		}
+		v.Field(i).Set(value)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,152 @@
+package locator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type PopulatedConfig struct {
+	Service  *TestService        `locator:"inject"`
+	Another  *AnotherTestService `locator:"inject"`
+	Port     int
+	internal *TestService `locator:"inject"`
+}
+
+// Test Populate assigns tagged exported fields
+func TestPopulate(t *testing.T) {
+	sl := locator.New()
+
+	service := &TestService{Name: "Service"}
+	locator.RegisterSingleton(sl, service)
+	locator.RegisterLazySingleton(sl, func() *AnotherTestService {
+		return &AnotherTestService{ID: 7}
+	})
+	locator.RegisterSingleton(sl, 8080)
+
+	var config PopulatedConfig
+	if err := locator.Populate(sl, &config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config.Service != service {
+		t.Fatalf("expected the registered service, got %v", config.Service)
+	}
+	if config.Another == nil || config.Another.ID != 7 {
+		t.Fatalf("expected ID 7, got %v", config.Another)
+	}
+	if config.Port != 0 {
+		t.Fatalf("expected untagged field to be skipped, got %v", config.Port)
+	}
+	if config.internal != nil {
+		t.Fatalf("expected unexported field to be skipped")
+	}
+}
+
+// Test Populate reports every unresolvable field
+func TestPopulateMissing(t *testing.T) {
+	sl := locator.New()
+
+	var config PopulatedConfig
+	err := locator.Populate(sl, &config)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectedError := "field Service: no provider registered for type *locator_test.TestService\n" +
+		"field Another: no provider registered for type *locator_test.AnotherTestService"
+	if err.Error() != expectedError {
+		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+}
+
+// Test Populate rejects targets that are not struct pointers
+func TestPopulateInvalidTarget(t *testing.T) {
+	sl := locator.New()
+
+	var nilConfig *PopulatedConfig
+	for _, target := range []any{PopulatedConfig{}, nilConfig, new(int), nil} {
+		if err := locator.Populate(sl, target); err == nil {
+			t.Fatalf("expected error for %T, got nil", target)
+		}
+	}
+}
+
+type WiredHandler struct {
+	Service *TestService
+	Greeter Greeter
+	Name    string `locator:"-"`
+	count   int
+}
+
+// Test Fill assigns every exported field not tagged to be skipped
+func TestFill(t *testing.T) {
+	sl := locator.New()
+
+	service := &TestService{Name: "Service"}
+	locator.RegisterSingleton(sl, service)
+	locator.RegisterAs[Greeter](sl, baseGreeter{})
+
+	handler := WiredHandler{Name: "kept"}
+	if err := locator.Fill(sl, &handler); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if handler.Service != service {
+		t.Fatalf("expected the registered service, got %v", handler.Service)
+	}
+	if handler.Greeter == nil || handler.Greeter.Greet() != "hello" {
+		t.Fatalf("expected the registered greeter, got %v", handler.Greeter)
+	}
+	if handler.Name != "kept" || handler.count != 0 {
+		t.Fatalf("expected skipped and unexported fields to be untouched")
+	}
+
+	err := locator.Fill(locator.New(), &handler)
+	if err == nil || !strings.Contains(err.Error(), "field Greeter: no provider registered for type locator_test.Greeter") {
+		t.Fatalf("expected missing Greeter error, got %v", err)
+	}
+}
+
+type ReplicatedStore struct {
+	Primary *TestService `locator:"inject"`
+	Replica *TestService `locator:"name=replica"`
+	Cache   Greeter      `locator:"optional"`
+	Backup  *TestService `locator:"name=backup,optional"`
+}
+
+// Test the name and optional tag options select named registrations and tolerate
+// missing ones
+func TestPopulateQualifiers(t *testing.T) {
+	sl := locator.New()
+
+	primary := &TestService{Name: "primary"}
+	replica := &TestService{Name: "replica"}
+	locator.RegisterSingleton(sl, primary)
+	locator.RegisterSingletonNamed(sl, "replica", replica)
+
+	var store ReplicatedStore
+	if err := locator.Populate(sl, &store); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if store.Primary != primary || store.Replica != replica {
+		t.Fatalf("expected primary and replica, got %v and %v", store.Primary, store.Replica)
+	}
+	if store.Cache != nil || store.Backup != nil {
+		t.Fatalf("expected optional fields to be left untouched, got %v and %v", store.Cache, store.Backup)
+	}
+
+	err := locator.Fill(locator.New(), &store)
+	if err == nil || !strings.Contains(err.Error(), "field Replica: no provider registered for type *locator_test.TestService[string(replica)]") {
+		t.Fatalf("expected missing replica error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Cache") || strings.Contains(err.Error(), "Backup") {
+		t.Fatalf("expected optional fields not to be reported, got %v", err)
+	}
+
+	var invalid struct {
+		Service *TestService `locator:"inject,primary"`
+	}
+	err = locator.Populate(sl, &invalid)
+	if err == nil || !strings.Contains(err.Error(), `field Service: unknown locator tag option "primary"`) {
+		t.Fatalf("expected unknown option error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package locatorprom exports Prometheus metrics about the resolutions made by a
+// locator. It plugs in as a resolve hook, so the core module stays free of the
+// Prometheus dependency
+package locatorprom
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector records locator resolutions as Prometheus metrics. Install its
+// Observe method with ServiceLocator.OnResolve and register the collector with a
+// prometheus.Registerer. Metrics are labelled with the resolved type only, never
+// with the key or name of a registration, so their cardinality is bounded by the
+// number of types
+type Collector struct {
+	resolutions *prometheus.CounterVec
+	errors      *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+}
+
+// NewCollector creates a Collector whose metrics are prefixed with namespace
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		resolutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "locator_resolutions_total",
+			Help:      "Resolutions per type, including failed ones.",
+		}, []string{"type"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "locator_resolution_errors_total",
+			Help:      "Failed resolutions per type, including types that are not registered.",
+		}, []string{"type"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "locator_resolution_duration_seconds",
+			Help:      "Time spent resolving per type, including running providers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+	}
+}
+
+// Observe records a resolution of t. It is a locator.ResolveHook, meant to be
+// passed to ServiceLocator.OnResolve
+func (c *Collector) Observe(t reflect.Type, instance any, d time.Duration, err error) {
+	typ := t.String()
+	c.resolutions.WithLabelValues(typ).Inc()
+	if err != nil {
+		c.errors.WithLabelValues(typ).Inc()
+	}
+	c.duration.WithLabelValues(typ).Observe(d.Seconds())
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.resolutions.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.resolutions.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+}
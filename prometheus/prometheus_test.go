@@ -0,0 +1,97 @@
+package locatorprom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+	locatorprom "github.com/RobinHood3082/locator/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type Database struct{}
+
+type Tenant struct{ ID string }
+
+// newCollector returns a collector registered with a fresh registry
+func newCollector() (*locatorprom.Collector, *prometheus.Registry) {
+	collector := locatorprom.NewCollector("app")
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	return collector, registry
+}
+
+// Test the collector counts resolutions and errors per type
+func TestCollector(t *testing.T) {
+	collector, registry := newCollector()
+	sl := locator.New()
+	sl.OnResolve(collector.Observe)
+	locator.RegisterLazySingleton(sl, func() *Database { return &Database{} })
+
+	locator.Get[*Database](sl)
+	locator.Get[*Database](sl)
+	locator.Get[string](sl)
+
+	expected := `
+# HELP app_locator_resolution_errors_total Failed resolutions per type, including types that are not registered.
+# TYPE app_locator_resolution_errors_total counter
+app_locator_resolution_errors_total{type="string"} 1
+# HELP app_locator_resolutions_total Resolutions per type, including failed ones.
+# TYPE app_locator_resolutions_total counter
+app_locator_resolutions_total{type="*locatorprom_test.Database"} 2
+app_locator_resolutions_total{type="string"} 1
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"app_locator_resolutions_total", "app_locator_resolution_errors_total")
+	if err != nil {
+		t.Fatalf("expected matching metrics, got %v", err)
+	}
+	if count := testutil.CollectAndCount(collector, "app_locator_resolution_duration_seconds"); count != 2 {
+		t.Fatalf("expected 2 duration histograms, got %d", count)
+	}
+}
+
+// Test keyed registrations are labelled with their type, not their key
+func TestCollectorKeyed(t *testing.T) {
+	collector, registry := newCollector()
+	sl := locator.New()
+	sl.OnResolve(collector.Observe)
+	locator.RegisterKeyedFactory(sl, "acme", func() *Tenant { return &Tenant{ID: "acme"} })
+	locator.RegisterKeyedFactory(sl, "globex", func() *Tenant { return &Tenant{ID: "globex"} })
+
+	locator.GetKeyed[string, *Tenant](sl, "acme")
+	locator.GetKeyed[string, *Tenant](sl, "globex")
+
+	expected := `
+# HELP app_locator_resolutions_total Resolutions per type, including failed ones.
+# TYPE app_locator_resolutions_total counter
+app_locator_resolutions_total{type="*locatorprom_test.Tenant"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "app_locator_resolutions_total"); err != nil {
+		t.Fatalf("expected a single series for the type, got %v", err)
+	}
+}
+
+// Test the collector leaves the observer slot free and sees scope resolutions
+func TestCollectorWithObserver(t *testing.T) {
+	collector, registry := newCollector()
+	var observed int
+	sl := locator.New(locator.WithObserver(func(locator.ResolveEvent) { observed++ }))
+	sl.OnResolve(collector.Observe)
+	locator.RegisterSingleton(sl, &Database{})
+
+	locator.Get[*Database](sl.Scope())
+
+	if observed != 1 {
+		t.Fatalf("expected the observer to see 1 resolution, got %d", observed)
+	}
+	expected := `
+# HELP app_locator_resolutions_total Resolutions per type, including failed ones.
+# TYPE app_locator_resolutions_total counter
+app_locator_resolutions_total{type="*locatorprom_test.Database"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "app_locator_resolutions_total"); err != nil {
+		t.Fatalf("expected matching metrics, got %v", err)
+	}
+}
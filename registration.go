@@ -0,0 +1,97 @@
+package locator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Registration is a typed registration captured for later application by
+// RegisterAll. Build one with Singleton, Lazy or Factory
+type Registration struct {
+	apply func(sl *ServiceLocator)
+}
+
+// Singleton returns a Registration that registers instance as a singleton
+func Singleton[T any](instance T) Registration {
+	return Registration{apply: func(sl *ServiceLocator) {
+		RegisterSingleton(sl, instance)
+	}}
+}
+
+// Lazy returns a Registration that registers provider as a lazy singleton
+func Lazy[T any](provider Provider[T]) Registration {
+	return Registration{apply: func(sl *ServiceLocator) {
+		RegisterLazySingleton(sl, provider)
+	}}
+}
+
+// Factory returns a Registration that registers provider as a factory
+func Factory[T any](provider Provider[T]) Registration {
+	return Registration{apply: func(sl *ServiceLocator) {
+		RegisterFactory(sl, provider)
+	}}
+}
+
+// RegisterAll applies regs in order, so a later registration for a type overwrites
+// an earlier one exactly as the equivalent sequence of direct calls would
+func RegisterAll(sl *ServiceLocator, regs ...Registration) {
+	for _, reg := range regs {
+		if reg.apply != nil {
+			reg.apply(sl)
+		}
+	}
+}
+
+// RegistrationInfo describes a registration for introspection
+type RegistrationInfo struct {
+	// Type is the name of the registered type, qualified by its key for keyed and
+	// named registrations
+	Type string
+	// Kind is the lifetime of the registration
+	Kind Kind
+	// Instantiated reports whether the locator holds an instance, which is always
+	// the case for singletons and once built for lazy singletons
+	Instantiated bool
+	// CallSite is the file and line of the latest registration call
+	CallSite string
+	// Duration is how long the provider took to build the instance held, 0 if
+	// unknown
+	Duration time.Duration
+}
+
+// Registrations describes every registration of the locator, sorted by type, for
+// example to print the wiring at startup. Nothing is constructed
+func (sl *ServiceLocator) Registrations() []RegistrationInfo {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	infos := make([]RegistrationInfo, 0, len(sl.providers)+len(sl.instances))
+	for typeKey, provider := range sl.providers {
+		info := RegistrationInfo{Type: fmt.Sprint(typeKey), CallSite: sl.sites[typeKey]}
+		if r, ok := provider.(resolver); ok {
+			info.Kind = r.kind()
+		}
+		_, info.Instantiated = sl.instances[typeKey]
+		if timed, ok := provider.(interface{ buildDuration() time.Duration }); ok && info.Instantiated {
+			info.Duration = timed.buildDuration()
+		}
+		infos = append(infos, info)
+	}
+	for typeKey := range sl.instances {
+		if _, exists := sl.providers[typeKey]; exists {
+			continue
+		}
+		infos = append(infos, RegistrationInfo{
+			Type:         fmt.Sprint(typeKey),
+			Kind:         KindSingleton,
+			Instantiated: true,
+			CallSite:     sl.sites[typeKey],
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Type < infos[j].Type
+	})
+	return infos
+}
@@ -0,0 +1,178 @@
+package locator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test RegisterAll applies every registration in order
+func TestRegisterAll(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterAll(sl,
+		locator.Singleton(&TestService{Name: "First"}),
+		locator.Lazy(func() *AnotherTestService {
+			return &AnotherTestService{ID: 1}
+		}),
+		locator.Factory(func() int {
+			return 42
+		}),
+		locator.Singleton(&TestService{Name: "Second"}),
+		locator.Registration{},
+	)
+
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service.Name != "Second" {
+		t.Fatalf("expected the later registration to win, got %v", service.Name)
+	}
+
+	another, err := locator.Get[*AnotherTestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if another.ID != 1 {
+		t.Fatalf("expected ID 1, got %v", another.ID)
+	}
+
+	value, err := locator.Get[int](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value != 42 {
+		t.Fatalf("expected 42, got %v", value)
+	}
+}
+
+// Test default registrations only install when nothing is registered
+func TestRegisterDefault(t *testing.T) {
+	sl := locator.New()
+
+	appService := &TestService{Name: "App"}
+	locator.RegisterSingleton(sl, appService)
+
+	if locator.RegisterDefaultSingleton(sl, &TestService{Name: "Default"}) {
+		t.Fatalf("expected the default singleton not to be installed")
+	}
+	if locator.RegisterDefaultFactory(sl, func() *TestService { return &TestService{Name: "Default"} }) {
+		t.Fatalf("expected the default factory not to be installed")
+	}
+	service, err := locator.Get[*TestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if service != appService {
+		t.Fatalf("expected the application registration to be kept, got %v", service.Name)
+	}
+
+	if !locator.RegisterDefaultLazySingleton(sl, func() *AnotherTestService { return &AnotherTestService{ID: 1} }) {
+		t.Fatalf("expected the default lazy singleton to be installed")
+	}
+	if locator.RegisterDefaultLazySingleton(sl, func() *AnotherTestService { return &AnotherTestService{ID: 2} }) {
+		t.Fatalf("expected the second default not to be installed")
+	}
+	another, err := locator.Get[*AnotherTestService](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if another.ID != 1 {
+		t.Fatalf("expected ID 1, got %v", another.ID)
+	}
+
+	if !locator.RegisterDefaultSingleton(sl, 42) {
+		t.Fatalf("expected the default singleton to be installed")
+	}
+}
+
+type Counter struct {
+	Count int
+}
+
+// Test RegisterSingletonWithMigrate carries state over to the new instance
+func TestRegisterSingletonWithMigrate(t *testing.T) {
+	sl := locator.New()
+
+	migrate := func(old, new *Counter) *Counter {
+		new.Count = old.Count
+		return new
+	}
+
+	first := &Counter{}
+	locator.RegisterSingletonWithMigrate(sl, first, migrate)
+	first.Count = 5
+
+	second := &Counter{}
+	locator.RegisterSingletonWithMigrate(sl, second, migrate)
+
+	counter, err := locator.Get[*Counter](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if counter != second {
+		t.Fatalf("expected the new instance to be registered")
+	}
+	if counter.Count != 5 {
+		t.Fatalf("expected the count to be migrated, got %d", counter.Count)
+	}
+}
+
+// Test RegisterSingletonWithMigrate does not migrate when the registration is rejected
+func TestRegisterSingletonWithMigrateRejected(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &Counter{Count: 5})
+	sl.Freeze()
+
+	var migrated bool
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, locator.ErrFrozen) {
+			t.Fatalf("expected %v, got %v", locator.ErrFrozen, err)
+		}
+		if migrated {
+			t.Fatalf("expected migrate not to run")
+		}
+	}()
+	locator.RegisterSingletonWithMigrate(sl, &Counter{}, func(old, new *Counter) *Counter {
+		migrated = true
+		return new
+	})
+}
+
+// Test Registrations describes kinds, instantiation and call sites
+func TestRegistrations(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &AnotherTestService{})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.RegisterFactory(sl, func() *Counter { return &Counter{} })
+	locator.RegisterAll(sl, locator.Singleton(42))
+	locator.Get[*TestService](sl)
+
+	infos := sl.Registrations()
+	expected := []locator.RegistrationInfo{
+		{Type: "*locator_test.AnotherTestService", Kind: locator.KindSingleton, Instantiated: true},
+		{Type: "*locator_test.Counter", Kind: locator.KindFactory},
+		{Type: "*locator_test.TestService", Kind: locator.KindLazySingleton, Instantiated: true},
+		{Type: "int", Kind: locator.KindSingleton, Instantiated: true},
+	}
+	if len(infos) != len(expected) {
+		t.Fatalf("expected %d registrations, got %+v", len(expected), infos)
+	}
+	for i, info := range infos {
+		if !strings.Contains(info.CallSite, "registration_test.go:") {
+			t.Fatalf("expected a call site in registration_test.go, got %q", info.CallSite)
+		}
+		if (info.Duration > 0) != (info.Kind == locator.KindLazySingleton) {
+			t.Fatalf("expected a build duration only for the built lazy singleton, got %+v", info)
+		}
+		info.CallSite, info.Duration = "", 0
+		if info != expected[i] {
+			t.Fatalf("expected %+v at position %d, got %+v", expected[i], i, info)
+		}
+	}
+}
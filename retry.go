@@ -0,0 +1,41 @@
+package locator
+
+import "time"
+
+// RetryPolicy decides how long a lazy singleton whose provider failed keeps
+// returning the error before the next Get builds it again. It is called with the
+// number of consecutive failures, starting at 1. A negative duration keeps the
+// error for good
+type RetryPolicy func(failures int) time.Duration
+
+// RetryAlways makes every Get after a failure build the lazy singleton again. It
+// is the behavior without WithLazyRetry
+func RetryAlways() RetryPolicy {
+	return func(int) time.Duration {
+		return 0
+	}
+}
+
+// CacheError makes a lazy singleton whose provider failed return the error on
+// every later Get without building it again
+func CacheError() RetryPolicy {
+	return func(int) time.Duration {
+		return -1
+	}
+}
+
+// RetryBackoff makes a lazy singleton whose provider failed return the error
+// until initial has passed, doubling the wait after each consecutive failure up to
+// max, so a failing dependency such as a database is not hammered by every Get
+func RetryBackoff(initial, max time.Duration) RetryPolicy {
+	return func(failures int) time.Duration {
+		wait := initial
+		for i := 1; i < failures && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+		return wait
+	}
+}
@@ -0,0 +1,77 @@
+package locator_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test each retry policy decides when a failed lazy singleton is built again
+func TestLazyRetry(t *testing.T) {
+	errDown := errors.New("database down")
+	for _, tc := range []struct {
+		name   string
+		policy locator.RetryPolicy
+		builds int
+	}{
+		{"default", nil, 3},
+		{"RetryAlways", locator.RetryAlways(), 3},
+		{"CacheError", locator.CacheError(), 1},
+		{"RetryBackoff", locator.RetryBackoff(time.Hour, time.Hour), 1},
+	} {
+		var opts []locator.Option
+		if tc.policy != nil {
+			opts = append(opts, locator.WithLazyRetry(tc.policy))
+		}
+		sl := locator.New(opts...)
+
+		var builds int
+		locator.RegisterLazySingletonE(sl, func() (*TestService, error) {
+			builds++
+			return nil, errDown
+		})
+		for i := 0; i < 3; i++ {
+			if _, err := locator.Get[*TestService](sl); !errors.Is(err, errDown) {
+				t.Fatalf("%s: expected %v, got %v", tc.name, errDown, err)
+			}
+		}
+		if builds != tc.builds {
+			t.Fatalf("%s: expected %d builds, got %d", tc.name, tc.builds, builds)
+		}
+	}
+}
+
+// Test RetryBackoff retries once the wait has passed and recovers on success
+func TestLazyRetryBackoff(t *testing.T) {
+	sl := locator.New(locator.WithLazyRetry(locator.RetryBackoff(20*time.Millisecond, time.Second)))
+
+	errDown := errors.New("database down")
+	var builds int
+	locator.RegisterLazySingletonE(sl, func() (*TestService, error) {
+		builds++
+		if builds == 1 {
+			return nil, errDown
+		}
+		return &TestService{Name: "Service"}, nil
+	})
+
+	if _, err := locator.Get[*TestService](sl); !errors.Is(err, errDown) {
+		t.Fatalf("expected %v, got %v", errDown, err)
+	}
+	if _, err := locator.Get[*TestService](sl); !errors.Is(err, errDown) || builds != 1 {
+		t.Fatalf("expected the cached error without a build, got %v after %d builds", err, builds)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := locator.Get[*TestService](sl); err != nil || builds != 2 {
+		t.Fatalf("expected a successful retry, got %v after %d builds", err, builds)
+	}
+
+	policy := locator.RetryBackoff(time.Second, 5*time.Second)
+	for failures, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if wait := policy(failures); wait != expected {
+			t.Fatalf("expected %v after %d failures, got %v", expected, failures, wait)
+		}
+	}
+}
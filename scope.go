@@ -0,0 +1,169 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Scope returns a child locator that inherits every registration of sl. Types
+// registered with RegisterScoped get one instance per scope, created on first use
+// and discarded by Close. Registering on the scope only affects the scope, and
+// singletons resolved through it are shared with sl
+func (sl *ServiceLocator) Scope() *ServiceLocator {
+	return NewChild(sl)
+}
+
+// NewChild creates a locator that falls back to parent for every type it has no
+// registration for, so per-tenant or per-plugin locators can share the
+// infrastructure registered on a root. The child starts with the options of parent
+// and applies opts on top of them. Like a scope, it creates its own instances of
+// types registered with RegisterScoped
+func NewChild(parent *ServiceLocator, opts ...Option) *ServiceLocator {
+	child := New()
+	child.parent = parent
+	child.opts = parent.opts
+	// Capped so that middlewares added to the child never reach the parent
+	mws := parent.opts.middlewares
+	child.opts.middlewares = mws[:len(mws):len(mws)]
+	for _, opt := range opts {
+		opt(&child.opts)
+	}
+	return child
+}
+
+// Close ends a scope, discarding the instances of scoped types it created and
+// closing those that implement io.Closer, most recently created first. Resolving a
+// scoped type from a closed scope fails; inherited registrations keep working
+func (sl *ServiceLocator) Close() error {
+	sl.mu.Lock()
+	var closers []io.Closer
+	for i := len(sl.scoped) - 1; i >= 0; i-- {
+		typeKey := sl.scoped[i]
+		if closer, ok := sl.instances[typeKey].(io.Closer); ok {
+			closers = append(closers, closer)
+		}
+		delete(sl.instances, typeKey)
+		delete(sl.providers, typeKey)
+	}
+	sl.scoped = nil
+	sl.closed = true
+	sl.changed()
+	sl.mu.Unlock()
+
+	var errs []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %T: %w", closer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterScoped registers a provider function that creates one instance of T per
+// scope, for example a unit of work shared by everything resolved for a request.
+// Scoped types can only be resolved from a locator returned by Scope
+func RegisterScoped[T any](sl *ServiceLocator, provider Provider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, scopedProvider[T]{provider: provider.withLocator().withError()}, false)
+}
+
+// RegisterScopedWithLocator registers a provider function that receives the scope
+// it builds an instance for, so it can resolve the other scoped types of that
+// scope, and creates one instance of T per scope
+func RegisterScopedWithLocator[T any](sl *ServiceLocator, provider LocatorProvider[T]) {
+	if !acceptProvider[T](sl, provider == nil) {
+		return
+	}
+	registerProvider[T](sl, scopedProvider[T]{provider: provider.withError()}, false)
+}
+
+// scopedProvider creates the instance of a scoped type in each scope
+type scopedProvider[T any] struct {
+	provider func(*ServiceLocator) (T, error)
+}
+
+// resolve returns the instance of the scope sl, creating it if necessary
+func (sp scopedProvider[T]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	typeKey := getTypeKey[T]()
+	if sl.parent == nil {
+		return nil, false, fmt.Errorf("type %v is scoped and must be resolved from a Scope", typeKey)
+	}
+
+	sl.mu.Lock()
+	if sl.closed {
+		sl.mu.Unlock()
+		return nil, false, fmt.Errorf("cannot resolve scoped type %v: scope is closed", typeKey)
+	}
+	// The scope keeps its instance in a lazy singleton of its own, so concurrent
+	// resolutions within the scope share a single construction
+	r, ok := sl.providers[typeKey].(resolver)
+	if !ok {
+		r = &lazySingleton[T]{provider: sp.provider, scoped: true}
+		sl.providers[typeKey] = r
+		sl.changed()
+		sl.scoped = append(sl.scoped, typeKey)
+	}
+	sl.mu.Unlock()
+	return r.resolve(ctx, sl)
+}
+
+// guardScope checks, under WithScopeGuard, that no scoped instance of another
+// scope is being built on the calling goroutine while sl resolves the scoped type
+// typeKey. Unless it fails, it returns a function to call once the resolution is over
+func (sl *ServiceLocator) guardScope(typeKey any) (done func(), err error) {
+	if outer, ok := scopeBuilds.top(); ok && outer.registry != sl.registry {
+		return nil, fmt.Errorf("%w: %v resolved from another scope than the one building %v", ErrForeignScope, typeKey, outer.typeKey)
+	}
+	return scopeBuilds.push(scopeBuild{registry: sl.registry, typeKey: typeKey}), nil
+}
+
+// scopeBuild is a scoped instance being built for the scope owning registry
+type scopeBuild struct {
+	registry *registry
+	typeKey  any
+}
+
+// scopeBuilds tracks the scoped instances each goroutine builds under
+// WithScopeGuard, innermost on top
+var scopeBuilds goroutineStack[scopeBuild]
+
+// kind reports that sp creates an instance per scope
+func (sp scopedProvider[T]) kind() Kind {
+	return KindScoped
+}
+
+// isScopeFactory marks sp as a scopeFactory
+func (sp scopedProvider[T]) isScopeFactory() {}
+
+// scopeFactory is implemented by scopedProvider, whatever its type parameter
+type scopeFactory interface {
+	resolver
+	isScopeFactory()
+}
+
+// inheritedScoped returns the scoped provider an ancestor registered for typeKey.
+// It reports false when the nearest registration of typeKey is not scoped, in which
+// case the parent should resolve it. The caller must not hold sl.mu
+func (sl *ServiceLocator) inheritedScoped(typeKey any) (resolver, bool) {
+	for p := sl.parent; p != nil; p = p.parent {
+		view := p.view()
+		provider, hasProvider := view.providers[typeKey]
+		_, hasInstance := view.instances[typeKey]
+
+		if sf, ok := provider.(scopeFactory); ok {
+			return sf, true
+		}
+		// The instance an ancestor scope created for itself is not inherited
+		if r, ok := provider.(resolver); ok && r.kind() == KindScoped {
+			continue
+		}
+		if hasProvider || hasInstance {
+			return nil, false
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,212 @@
+package locator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type UnitOfWork struct {
+	ID     int
+	closed bool
+}
+
+func (u *UnitOfWork) Close() error {
+	u.closed = true
+	return nil
+}
+
+// Test scoped types get one instance per scope while singletons are shared
+func TestScope(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{Name: "Shared"} })
+	var created int
+	locator.RegisterScoped(sl, func() *UnitOfWork {
+		created++
+		return &UnitOfWork{ID: created}
+	})
+
+	if _, err := locator.Get[*UnitOfWork](sl); err == nil || !strings.Contains(err.Error(), "must be resolved from a Scope") {
+		t.Fatalf("expected scope error from the root, got %v", err)
+	}
+
+	first, second := sl.Scope(), sl.Scope()
+	uow1, err := locator.Get[*UnitOfWork](first)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	again, _ := locator.Get[*UnitOfWork](first)
+	uow2, _ := locator.Get[*UnitOfWork](second)
+	if uow1 != again {
+		t.Fatalf("expected one instance within a scope")
+	}
+	if uow1 == uow2 || created != 2 {
+		t.Fatalf("expected one instance per scope, got %d created", created)
+	}
+
+	shared1, _ := locator.Get[*TestService](first)
+	shared2, _ := locator.Get[*TestService](second)
+	root, _ := locator.Get[*TestService](sl)
+	if shared1 != shared2 || shared1 != root {
+		t.Fatalf("expected singletons to be shared with the parent")
+	}
+
+	// Registrations on a scope do not leak to the parent
+	locator.RegisterSingleton(first, &AnotherTestService{ID: 1})
+	if _, err := locator.Get[*AnotherTestService](sl); err == nil {
+		t.Fatalf("expected scope registration not to affect the parent")
+	}
+}
+
+// Test nested scopes create their own scoped instances
+func TestNestedScope(t *testing.T) {
+	sl := locator.New()
+	var created int
+	locator.RegisterScoped(sl, func() *UnitOfWork {
+		created++
+		return &UnitOfWork{ID: created}
+	})
+
+	outer := sl.Scope()
+	inner := outer.Scope()
+	uowOuter, _ := locator.Get[*UnitOfWork](outer)
+	uowInner, err := locator.Get[*UnitOfWork](inner)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if uowOuter == uowInner {
+		t.Fatalf("expected the inner scope to create its own instance")
+	}
+}
+
+// Test closing a scope discards and closes its scoped instances
+func TestScopeClose(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterScoped(sl, func() *UnitOfWork { return &UnitOfWork{} })
+	locator.RegisterSingleton(sl, &TestService{Name: "Shared"})
+
+	scope := sl.Scope()
+	uow, _ := locator.Get[*UnitOfWork](scope)
+	if err := scope.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !uow.closed {
+		t.Fatalf("expected the scoped instance to be closed")
+	}
+
+	if _, err := locator.Get[*UnitOfWork](scope); err == nil || !strings.Contains(err.Error(), "scope is closed") {
+		t.Fatalf("expected closed scope error, got %v", err)
+	}
+	if _, err := locator.Get[*TestService](scope); err != nil {
+		t.Fatalf("expected inherited registrations to keep working, got %v", err)
+	}
+}
+
+// Test a failing Close is reported
+func TestScopeCloseError(t *testing.T) {
+	sl := locator.New()
+	errClose := errors.New("close failed")
+	locator.RegisterScoped(sl, func() *failingCloser { return &failingCloser{err: errClose} })
+
+	scope := sl.Scope()
+	locator.Get[*failingCloser](scope)
+	if err := scope.Close(); !errors.Is(err, errClose) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+}
+
+type failingCloser struct {
+	err error
+}
+
+func (f *failingCloser) Close() error {
+	return f.err
+}
+
+// Test a child locator falls back to its parent and keeps its own registrations
+func TestNewChild(t *testing.T) {
+	root := locator.New()
+	shared := &TestService{Name: "Shared"}
+	locator.RegisterSingleton(root, shared)
+	locator.RegisterSingleton(root, &AnotherTestService{ID: 1})
+
+	var tenantMiddleware int
+	tenant := locator.NewChild(root, locator.WithMiddleware(func(ctx context.Context, typ string, next func(context.Context) (any, error)) (any, error) {
+		tenantMiddleware++
+		return next(ctx)
+	}))
+	locator.RegisterFactory(tenant, func() *AnotherTestService { return &AnotherTestService{ID: 2} })
+
+	if service, err := locator.Get[*TestService](tenant); err != nil || service != shared {
+		t.Fatalf("expected the shared instance from the parent, got %v, %v", service, err)
+	}
+	if service, err := locator.Get[*AnotherTestService](tenant); err != nil || service.ID != 2 {
+		t.Fatalf("expected the child registration, got %v, %v", service, err)
+	}
+	if service, err := locator.Get[*AnotherTestService](root); err != nil || service.ID != 1 {
+		t.Fatalf("expected the parent to be unaffected, got %v, %v", service, err)
+	}
+	if tenantMiddleware != 1 {
+		t.Fatalf("expected the child middleware to run once, got %d", tenantMiddleware)
+	}
+}
+
+type Transaction struct {
+	uow *UnitOfWork
+}
+
+// Test WithScopeGuard rejects scoped types resolved from a sibling or parent scope
+// while a scoped instance is built
+func TestScopeGuard(t *testing.T) {
+	sl := locator.New(locator.WithScopeGuard())
+	locator.RegisterScoped(sl, func() *UnitOfWork { return &UnitOfWork{} })
+	// from is the locator the provider resolves from, nil for the one it is handed
+	var from *locator.ServiceLocator
+	var resolveErr error
+	locator.RegisterScopedWithLocator(sl, func(scope *locator.ServiceLocator) *Transaction {
+		if from != nil {
+			scope = from
+		}
+		uow, err := locator.Get[*UnitOfWork](scope)
+		resolveErr = err
+		return &Transaction{uow: uow}
+	})
+
+	a, b := sl.Scope(), sl.Scope()
+	tx, err := locator.Get[*Transaction](a)
+	if err != nil || resolveErr != nil {
+		t.Fatalf("expected no error, got %v, %v", err, resolveErr)
+	}
+	if uow, _ := locator.Get[*UnitOfWork](a); tx.uow != uow {
+		t.Fatalf("expected the transaction to use the unit of work of its scope")
+	}
+
+	from = b
+	locator.Get[*Transaction](sl.Scope())
+	if !errors.Is(resolveErr, locator.ErrForeignScope) {
+		t.Fatalf("expected %v for a sibling scope, got %v", locator.ErrForeignScope, resolveErr)
+	}
+
+	from = a
+	locator.Get[*Transaction](a.Scope())
+	if !errors.Is(resolveErr, locator.ErrForeignScope) {
+		t.Fatalf("expected %v for a parent scope, got %v", locator.ErrForeignScope, resolveErr)
+	}
+
+	// Without the guard the leak goes unnoticed
+	unguarded := locator.New()
+	locator.RegisterScoped(unguarded, func() *UnitOfWork { return &UnitOfWork{} })
+	other := unguarded.Scope()
+	locator.RegisterScoped(unguarded, func() *Transaction {
+		uow, err := locator.Get[*UnitOfWork](other)
+		resolveErr = err
+		return &Transaction{uow: uow}
+	})
+	locator.Get[*Transaction](unguarded.Scope())
+	if resolveErr != nil {
+		t.Fatalf("expected no error without the guard, got %v", resolveErr)
+	}
+}
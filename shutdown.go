@@ -0,0 +1,139 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// shutdowner is implemented by services with a context aware shutdown, such as
+// http.Server
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// Shutdown releases the singletons the locator holds, eager and materialized lazy
+// alike, calling Shutdown(ctx) on those that implement it and Close on those that
+// implement io.Closer. Instances are released in ShutdownOrder and an instance
+// registered under several types is released once. Errors are collected and
+// returned together, and Shutdown stops early if ctx is done. Registrations are
+// kept
+func (sl *ServiceLocator) Shutdown(ctx context.Context) error {
+	sl.mu.RLock()
+	typeKeys := sl.shutdownOrder()
+	instances := make([]any, len(typeKeys))
+	for i, typeKey := range typeKeys {
+		instances[i] = sl.instances[typeKey]
+	}
+	sl.mu.RUnlock()
+
+	var errs []error
+	released := make(map[any]bool)
+	for i, instance := range instances {
+		if instance == nil || !distinct(released, instance) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		var err error
+		switch s := instance.(type) {
+		case shutdowner:
+			err = s.Shutdown(ctx)
+		case io.Closer:
+			err = s.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shutdown %v: %w", typeKeys[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// distinct reports whether instance is not in seen yet and records it. Instances
+// that cannot be map keys, such as structs holding a slice in an interface field,
+// are always distinct
+func distinct(seen map[any]bool, instance any) bool {
+	if !reflect.ValueOf(instance).Comparable() {
+		return true
+	}
+	if seen[instance] {
+		return false
+	}
+	seen[instance] = true
+	return true
+}
+
+// ShutdownOrder returns the names of the types holding instances in the order
+// Shutdown releases them: the reverse of the order they were constructed or
+// registered in, adjusted so that a service is released before everything it
+// depends on according to DependsOn and the dependencies recorded during
+// resolution
+func (sl *ServiceLocator) ShutdownOrder() []string {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	order := sl.shutdownOrder()
+	names := make([]string, len(order))
+	for i, typeKey := range order {
+		names[i] = fmt.Sprint(typeKey)
+	}
+	return names
+}
+
+// shutdownOrder returns the keys of the instances in the order they are released.
+// The caller must hold sl.mu
+func (sl *ServiceLocator) shutdownOrder() []any {
+	constructed := sl.constructionOrder()
+	position := make(map[any]int, len(constructed))
+	for i, typeKey := range constructed {
+		position[typeKey] = i
+	}
+
+	dependents := make(map[any][]any)
+	addDependent := func(typeKey, dep any) {
+		if _, ok := position[typeKey]; ok {
+			dependents[dep] = append(dependents[dep], typeKey)
+		}
+	}
+	for typeKey, deps := range sl.dependencies {
+		for _, dep := range deps {
+			addDependent(typeKey, dep)
+		}
+	}
+	for typeKey, deps := range sl.edges {
+		for dep := range deps {
+			addDependent(typeKey, dep)
+		}
+	}
+	// Visit the most recently constructed dependents first
+	for dep := range dependents {
+		sort.Slice(dependents[dep], func(i, j int) bool {
+			return position[dependents[dep][i]] > position[dependents[dep][j]]
+		})
+	}
+
+	// Every dependent is released before the services it depends on; otherwise
+	// the most recently constructed instance goes first
+	visited := make(map[any]bool, len(constructed))
+	order := make([]any, 0, len(constructed))
+	var visit func(typeKey any)
+	visit = func(typeKey any) {
+		if visited[typeKey] {
+			return
+		}
+		visited[typeKey] = true
+		for _, dependent := range dependents[typeKey] {
+			visit(dependent)
+		}
+		order = append(order, typeKey)
+	}
+	for i := len(constructed) - 1; i >= 0; i-- {
+		visit(constructed[i])
+	}
+	return order
+}
@@ -0,0 +1,149 @@
+package locator_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type Stopper interface {
+	Shutdown(ctx context.Context) error
+}
+
+type Pool struct {
+	shutdownCalls int
+}
+
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.shutdownCalls++
+	return nil
+}
+
+// Test Shutdown closes eager and materialized lazy singletons
+func TestShutdown(t *testing.T) {
+	sl := locator.New()
+
+	uow := &UnitOfWork{}
+	locator.RegisterSingleton(sl, uow)
+	pool := &Pool{}
+	locator.RegisterLazySingleton(sl, func() *Pool { return pool })
+	// The same instance bound to an interface is only shut down once
+	locator.RegisterAs[Stopper](sl, pool)
+	var lazyBuilt bool
+	locator.RegisterLazySingleton(sl, func() *failingCloser {
+		lazyBuilt = true
+		return &failingCloser{}
+	})
+	locator.Get[*Pool](sl)
+
+	if err := sl.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !uow.closed {
+		t.Fatalf("expected the eager singleton to be closed")
+	}
+	if pool.shutdownCalls != 1 {
+		t.Fatalf("expected the lazy singleton to be shut down once, got %d", pool.shutdownCalls)
+	}
+	if lazyBuilt {
+		t.Fatalf("expected unbuilt lazy singletons not to be constructed")
+	}
+}
+
+// Test Shutdown collects errors
+func TestShutdownErrors(t *testing.T) {
+	sl := locator.New()
+
+	errClose := errors.New("close failed")
+	locator.RegisterSingleton(sl, &failingCloser{err: errClose})
+	locator.RegisterSingleton(sl, &UnitOfWork{})
+
+	if err := sl.Shutdown(context.Background()); !errors.Is(err, errClose) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sl.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// Test ShutdownOrder releases dependents before their dependencies
+func TestShutdownOrder(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &ServiceA{})
+	locator.RegisterSingleton(sl, &ServiceB{})
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceC {
+		locator.Get[*ServiceA](sl)
+		return &ServiceC{}
+	})
+	locator.RegisterSingleton(sl, &ServiceD{})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	// A was registered before B but uses it, so it must be released first
+	locator.DependsOn[*ServiceA](sl, typeB)
+	locator.Get[*ServiceC](sl)
+
+	expected := []string{typeC.String(), typeD.String(), typeA.String(), typeB.String()}
+	if order := sl.ShutdownOrder(); !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
+
+type recordingCloser struct {
+	name string
+	log  *[]string
+}
+
+func (r *recordingCloser) Close() error {
+	*r.log = append(*r.log, r.name)
+	return nil
+}
+
+// Test Shutdown releases instances in reverse registration order
+func TestShutdownReverseOrder(t *testing.T) {
+	sl := locator.New()
+
+	var log []string
+	for _, name := range []string{"pool", "cache", "repository"} {
+		locator.RegisterSingletonNamed(sl, name, &recordingCloser{name: name, log: &log})
+	}
+
+	if err := sl.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"repository", "cache", "pool"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("expected %v, got %v", expected, log)
+	}
+}
+
+// Holder is a comparable struct whose interface field may hold an unhashable value
+type Holder struct {
+	V      any
+	closed *int
+}
+
+func (h Holder) Close() error {
+	*h.closed++
+	return nil
+}
+
+// Test Shutdown releases values that cannot be used as map keys
+func TestShutdownUnhashable(t *testing.T) {
+	sl := locator.New()
+	var closed int
+	locator.RegisterSingleton(sl, Holder{V: []int{1}, closed: &closed})
+	locator.RegisterSingleton(sl, map[string]int{"a": 1})
+
+	if err := sl.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if closed != 1 {
+		t.Fatalf("expected the holder closed once, got %d", closed)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.21
+
+package locator
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// WithLogger makes the locator log registrations, overwrites, the first
+// construction of lazy singletons and resolution failures to l, with the type
+// name as the "type" attribute. Registrations are logged at debug level,
+// constructions at info, overwrites at warn and failures at error level
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = slogLogger{l: l}
+	}
+}
+
+// slogLogger logs locator events to a slog.Logger
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// registered logs a registration at debug level, or at warn level if it overwrote one
+func (s slogLogger) registered(typeKey any, ev RegisterEvent) {
+	attrs := []any{"type", fmt.Sprint(typeKey), "kind", ev.Kind.String(), "site", ev.CallSite}
+	if ev.Overwrite {
+		s.l.Warn("registration overwritten", attrs...)
+		return
+	}
+	s.l.Debug("service registered", attrs...)
+}
+
+// constructed logs the first construction of an instance at info level
+func (s slogLogger) constructed(typeKey any, kind Kind, d time.Duration) {
+	s.l.Info("service constructed", "type", fmt.Sprint(typeKey), "kind", kind.String(), "duration", d)
+}
+
+// failed logs a resolution failure at error level
+func (s slogLogger) failed(typeKey any, err error) {
+	s.l.Error("resolution failed", "type", fmt.Sprint(typeKey), "error", err)
+}
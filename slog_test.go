@@ -0,0 +1,48 @@
+//go:build go1.21
+
+package locator_test
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test WithLogger logs registrations, overwrites, constructions and failures
+func TestWithLogger(t *testing.T) {
+	var out strings.Builder
+	handler := slog.NewTextHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "site" || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	sl := locator.New(locator.WithLogger(slog.New(handler)))
+
+	locator.RegisterSingleton(sl, &TestService{})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.Get[*TestService](sl)
+	locator.Get[*TestService](sl)
+	locator.Get[string](sl)
+
+	expected := []string{
+		`level=DEBUG msg="service registered" type=*locator_test.TestService kind=singleton`,
+		`level=WARN msg="registration overwritten" type=*locator_test.TestService kind=lazy`,
+		`level=INFO msg="service constructed" type=*locator_test.TestService kind=lazy`,
+		`level=ERROR msg="resolution failed" type=string error="no provider registered for type string"`,
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d log lines, got %d:\n%s", len(expected), len(lines), out.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Fatalf("expected line %q, got %q", expected[i], line)
+		}
+	}
+}
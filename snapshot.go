@@ -0,0 +1,91 @@
+package locator
+
+// snapshot is an immutable copy of the registrations read by Get. The locator
+// keeps its current snapshot behind an atomic pointer, so resolving a cached
+// instance takes no lock. Every change made under sl.mu discards the snapshot and
+// the next resolution copies a new one, so a burst of registrations costs a
+// single copy
+type snapshot struct {
+	instances  map[any]cachedInstance
+	providers  map[any]any
+	decorators map[any]any
+	onResolve  []ResolveHook
+}
+
+// cachedInstance is an instance held by the locator, the kind of its
+// registration and its resolution counter
+type cachedInstance struct {
+	instance    any
+	kind        Kind
+	resolutions *stripedCounter
+}
+
+// has reports whether typeKey has an instance or a provider in s
+func (s *snapshot) has(typeKey any) bool {
+	if _, exists := s.instances[typeKey]; exists {
+		return true
+	}
+	_, exists := s.providers[typeKey]
+	return exists
+}
+
+// view returns the current snapshot of sl, copying one if a change discarded it.
+// The caller must not hold sl.mu
+func (sl *ServiceLocator) view() *snapshot {
+	if s := sl.snapshot.Load(); s != nil {
+		return s
+	}
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if s := sl.snapshot.Load(); s != nil {
+		return s
+	}
+	s := &snapshot{
+		instances:  make(map[any]cachedInstance, len(sl.instances)),
+		providers:  make(map[any]any, len(sl.providers)),
+		decorators: make(map[any]any, len(sl.decorators)),
+		onResolve:  sl.onResolve[:len(sl.onResolve):len(sl.onResolve)],
+	}
+	for typeKey, instance := range sl.instances {
+		kind := KindSingleton
+		if r, ok := sl.providers[typeKey].(resolver); ok {
+			kind = r.kind()
+		}
+		s.instances[typeKey] = cachedInstance{instance: instance, kind: kind, resolutions: sl.counter(typeKey)}
+	}
+	for typeKey, provider := range sl.providers {
+		s.providers[typeKey] = provider
+	}
+	for typeKey, decorators := range sl.decorators {
+		s.decorators[typeKey] = decorators
+	}
+	sl.snapshot.Store(s)
+	return s
+}
+
+// changed discards the snapshot after a change to the registrations. The caller
+// must hold sl.mu for writing
+func (sl *ServiceLocator) changed() {
+	sl.snapshot.Store(nil)
+}
+
+// cached returns the instance held for typeKey when resolving it needs nothing
+// but a lookup: no hook, observer, logger, tracer or locator check is installed
+// and sl is neither a scope nor a view handed to a provider. typeKey does not
+// escape, so callers can build keys on the stack
+func (sl *ServiceLocator) cached(typeKey any) (any, bool) {
+	if sl.parent != nil || sl.frame != nil || sl.opts.observer != nil || sl.opts.logger != nil || sl.opts.tracer != nil || sl.opts.locatorCheck != nil {
+		return nil, false
+	}
+	view := sl.view()
+	if len(view.onResolve) > 0 {
+		return nil, false
+	}
+	cached, exists := view.instances[typeKey]
+	if !exists {
+		return nil, false
+	}
+	cached.resolutions.add()
+	return cached.instance, true
+}
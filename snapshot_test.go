@@ -0,0 +1,64 @@
+package locator_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test registrations made between resolutions are visible to the next Get
+func TestSnapshotSeesChanges(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterSingleton(sl, &TestService{Name: "first"})
+	if service, _ := locator.Get[*TestService](sl); service.Name != "first" {
+		t.Fatalf("expected first, got %v", service.Name)
+	}
+
+	locator.RegisterSingleton(sl, &TestService{Name: "second"})
+	locator.Decorate(sl, func(s *TestService) *TestService {
+		return &TestService{Name: "decorated " + s.Name}
+	})
+	if service, _ := locator.Get[*TestService](sl); service.Name != "decorated second" {
+		t.Fatalf("expected decorated second, got %v", service.Name)
+	}
+
+	locator.Unregister[*TestService](sl)
+	if _, err := locator.Get[*TestService](sl); err == nil {
+		t.Fatalf("expected error after Unregister, got nil")
+	}
+}
+
+// Test concurrent registrations and resolutions never observe a missing
+// registration that was made before they started
+func TestSnapshotConcurrent(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{Name: "Service"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				locator.RegisterSingletonNamed(sl, fmt.Sprintf("%d-%d", i, j), j)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := locator.Get[*TestService](sl); err != nil {
+					t.Errorf("expected no error, got %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n, err := locator.GetNamed[int](sl, "3-99"); err != nil || n != 99 {
+		t.Fatalf("expected 99, got %v, %v", n, err)
+	}
+}
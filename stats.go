@@ -0,0 +1,102 @@
+package locator
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the state of a locator
+type Stats struct {
+	// Providers is the number of registered providers
+	Providers int
+	// Instances is the number of singleton instances held, both registered and
+	// materialized by lazy singletons
+	Instances int
+	// Resolutions counts the resolutions of each type, including failed ones
+	Resolutions map[string]uint64
+	// NilInstances lists, sorted, the types whose singleton is a nil value such
+	// as a typed nil pointer. Registering one is allowed but usually a mistake
+	NilInstances []string
+}
+
+// Stats returns a snapshot of the locator's registrations and resolution counts.
+// The snapshot is a copy and is not updated by later resolutions
+func (sl *ServiceLocator) Stats() Stats {
+	sl.mu.RLock()
+	stats := Stats{
+		Providers:   len(sl.providers),
+		Instances:   len(sl.instances),
+		Resolutions: make(map[string]uint64),
+	}
+	for typeKey, instance := range sl.instances {
+		if isNil(instance) {
+			stats.NilInstances = append(stats.NilInstances, fmt.Sprint(typeKey))
+		}
+	}
+	sl.mu.RUnlock()
+	sort.Strings(stats.NilInstances)
+
+	sl.resolutions.Range(func(typeKey, count any) bool {
+		// Counters of cached instances are created before their first resolution
+		if n := count.(*stripedCounter).load(); n > 0 {
+			stats.Resolutions[fmt.Sprint(typeKey)] += n
+		}
+		return true
+	})
+	return stats
+}
+
+// countResolution increments the resolution counter of typeKey without taking the
+// locator lock
+func (sl *ServiceLocator) countResolution(typeKey any) {
+	sl.counter(typeKey).add()
+}
+
+// counter returns the resolution counter of typeKey, creating it if needed
+func (sl *ServiceLocator) counter(typeKey any) *stripedCounter {
+	count, ok := sl.resolutions.Load(typeKey)
+	if !ok {
+		count, _ = sl.resolutions.LoadOrStore(typeKey, newStripedCounter())
+	}
+	return count.(*stripedCounter)
+}
+
+// stripedCounter is a counter split over several cache lines, so goroutines
+// resolving the same type on different cores rarely write to the same line
+type stripedCounter struct {
+	stripes []paddedCounter
+}
+
+// paddedCounter is a counter filling a cache line of its own
+type paddedCounter struct {
+	n atomic.Uint64
+	_ [56]byte
+}
+
+// newStripedCounter returns a counter with one stripe per processor, rounded up
+// to a power of two
+func newStripedCounter() *stripedCounter {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return &stripedCounter{stripes: make([]paddedCounter, n)}
+}
+
+// add increments a stripe picked at random. The top-level functions of math/rand
+// use the lock-free per-thread generator of the runtime
+func (c *stripedCounter) add() {
+	c.stripes[rand.Uint32()&uint32(len(c.stripes)-1)].n.Add(1)
+}
+
+// load returns the sum of the stripes
+func (c *stripedCounter) load() uint64 {
+	var total uint64
+	for i := range c.stripes {
+		total += c.stripes[i].n.Load()
+	}
+	return total
+}
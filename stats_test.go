@@ -0,0 +1,87 @@
+package locator_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Stats reports registrations and per-type resolution counts
+func TestStats(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, 42)
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.RegisterFactory(sl, func() *AnotherTestService { return &AnotherTestService{} })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locator.Get[*TestService](sl)
+		}()
+	}
+	wg.Wait()
+	locator.Get[int](sl)
+	locator.Get[string](sl)
+
+	stats := sl.Stats()
+	if stats.Providers != 2 {
+		t.Fatalf("expected 2 providers, got %d", stats.Providers)
+	}
+	// The eager singleton and the materialized lazy singleton
+	if stats.Instances != 2 {
+		t.Fatalf("expected 2 instances, got %d", stats.Instances)
+	}
+	expected := map[string]uint64{"*locator_test.TestService": 10, "int": 1, "string": 1}
+	for typ, count := range expected {
+		if stats.Resolutions[typ] != count {
+			t.Fatalf("expected %d resolutions of %s, got %d", count, typ, stats.Resolutions[typ])
+		}
+	}
+	if _, exists := stats.Resolutions["*locator_test.AnotherTestService"]; exists {
+		t.Fatalf("expected no resolutions of an unresolved type, got %v", stats.Resolutions)
+	}
+
+	// The snapshot is not affected by later resolutions
+	locator.Get[int](sl)
+	if stats.Resolutions["int"] != 1 {
+		t.Fatalf("expected the snapshot to keep 1 resolution, got %d", stats.Resolutions["int"])
+	}
+}
+
+// Test Stats lists singletons registered as nil values
+func TestStatsNilInstances(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton[*TestService](sl, nil)
+	locator.RegisterSingleton[*Config](sl, nil)
+	locator.RegisterSingleton(sl, &AnotherTestService{})
+
+	expected := []string{"*locator_test.Config", "*locator_test.TestService"}
+	if nils := sl.Stats().NilInstances; !reflect.DeepEqual(nils, expected) {
+		t.Fatalf("expected %v, got %v", expected, nils)
+	}
+}
+
+// Test no resolution of a cached instance is lost when many goroutines count it
+func TestStatsConcurrentResolutions(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterSingleton(sl, &TestService{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				locator.Get[*TestService](sl)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := sl.Stats().Resolutions["*locator_test.TestService"]; n != 8000 {
+		t.Fatalf("expected 8000 resolutions, got %d", n)
+	}
+}
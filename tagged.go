@@ -0,0 +1,182 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterTagged adds a T to the services tagged with each of tags, which
+// GetByTag returns together regardless of their concrete types, for example every
+// "startup-hooks" or every "migrations". instanceOrProvider is either an instance
+// of T or a Provider, ProviderE, LocatorProvider or LocatorProviderE of T, which
+// is called once on first use and shared between the tags. Tagged services are
+// not registered under T and cannot be resolved with Get. Registration hooks and
+// subscribers see one registration per tag. It panics if instanceOrProvider is
+// neither, no tag is given or a registration hook rejects it
+func RegisterTagged[T any](sl *ServiceLocator, instanceOrProvider any, tags ...string) {
+	typ := getTypeKey[T]().(reflect.Type)
+	if len(tags) == 0 {
+		panic(fmt.Errorf("no tags given for tagged %v", typ))
+	}
+
+	var member taggedMember[T]
+	key := taggedKey{typ: typ, tag: strings.Join(tags, ",")}
+	switch v := instanceOrProvider.(type) {
+	case T:
+		member.instance = v
+	case Provider[T]:
+		member.provider = &lazySingleton[T]{provider: v.withLocator().withError(), key: key}
+	case func() T:
+		member.provider = &lazySingleton[T]{provider: Provider[T](v).withLocator().withError(), key: key}
+	case ProviderE[T]:
+		member.provider = &lazySingleton[T]{provider: v.withLocator(), key: key}
+	case func() (T, error):
+		member.provider = &lazySingleton[T]{provider: ProviderE[T](v).withLocator(), key: key}
+	case LocatorProvider[T]:
+		member.provider = &lazySingleton[T]{provider: v.withError(), key: key}
+	case func(*ServiceLocator) T:
+		member.provider = &lazySingleton[T]{provider: LocatorProvider[T](v).withError(), key: key}
+	case LocatorProviderE[T]:
+		member.provider = &lazySingleton[T]{provider: v, key: key}
+	case func(*ServiceLocator) (T, error):
+		member.provider = &lazySingleton[T]{provider: v, key: key}
+	default:
+		panic(fmt.Errorf("tagged %v must be an instance or a provider of it, got %T", typ, instanceOrProvider))
+	}
+
+	kind := KindSingleton
+	if member.provider != nil {
+		kind = KindLazySingleton
+	}
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	site := callerSite()
+	for _, tag := range tags {
+		groupKey := taggedKey{typ: typ, tag: tag}
+		sl.mustNotBeFrozen(groupKey)
+		if err := sl.announce(groupKey, kind, false, site); err != nil {
+			panic(err)
+		}
+	}
+	for _, tag := range tags {
+		groupKey := taggedKey{typ: typ, tag: tag}
+		existing, _ := sl.groups[groupKey].(taggedGroup[T])
+		// Copy on append so slices returned earlier are never modified
+		sl.groups[groupKey] = append(existing[:len(existing):len(existing)], member)
+	}
+}
+
+// GetByTag returns every T registered with RegisterTagged under tag, in
+// registration order, starting with those inherited by a scope. It returns an
+// empty slice if nothing is tagged with tag. If any provider fails, GetByTag
+// returns every failure joined
+func GetByTag[T any](sl *ServiceLocator, tag string) ([]T, error) {
+	members := taggedMembers[T](sl, taggedKey{typ: getTypeKey[T]().(reflect.Type), tag: tag})
+	all := make([]T, 0, len(members))
+	var errs []error
+	for _, member := range members {
+		if member.provider == nil {
+			all = append(all, member.instance)
+			continue
+		}
+		instance, _, err := member.provider.getInstance(context.Background(), sl)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tag %q: %w", tag, err))
+			continue
+		}
+		all = append(all, instance)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return all, nil
+}
+
+// taggedKey is the group key of the services of typ tagged with tag, and the key
+// a tagged provider is built under
+type taggedKey struct {
+	typ reflect.Type
+	tag string
+}
+
+// String returns the type followed by the tag, for error messages
+func (k taggedKey) String() string {
+	return fmt.Sprintf("%v[tag %s]", k.typ, k.tag)
+}
+
+// taggedMember is a tagged service: either an instance or, when provider is set,
+// a lazily built one
+type taggedMember[T any] struct {
+	instance T
+	provider *lazySingleton[T]
+}
+
+// taggedGroup is the group of the services of T tagged with a tag
+type taggedGroup[T any] []taggedMember[T]
+
+// resettableGroup is implemented by groups whose members cache instances that must
+// not be shared when the group is copied
+type resettableGroup interface {
+	// freshGroup returns a copy of the group with unmaterialized providers.
+	// renewed maps each provider already copied to its copy, so a provider shared
+	// between groups stays shared. With keepBuilt, members already built keep
+	// their instance
+	freshGroup(renewed map[any]any, keepBuilt bool) any
+}
+
+// freshGroup returns a copy of g with unmaterialized providers
+func (g taggedGroup[T]) freshGroup(renewed map[any]any, keepBuilt bool) any {
+	fresh := make(taggedGroup[T], len(g))
+	for i, member := range g {
+		if member.provider == nil {
+			fresh[i] = member
+			continue
+		}
+		if keepBuilt {
+			member.provider.mu.Lock()
+			done, instance := member.provider.done, member.provider.instance
+			member.provider.mu.Unlock()
+			if done {
+				fresh[i] = taggedMember[T]{instance: instance}
+				continue
+			}
+		}
+		provider, exists := renewed[member.provider]
+		if !exists {
+			provider = member.provider.fresh()
+			renewed[member.provider] = provider
+		}
+		fresh[i] = taggedMember[T]{provider: provider.(*lazySingleton[T])}
+	}
+	return fresh
+}
+
+// freshGroups replaces the groups of sl holding cached instances with fresh copies.
+// The caller must hold sl.mu for writing
+func (sl *ServiceLocator) freshGroups(groups map[any]any, keepBuilt bool) {
+	renewed := make(map[any]any)
+	for groupKey, members := range groups {
+		if g, ok := members.(resettableGroup); ok {
+			sl.groups[groupKey] = g.freshGroup(renewed, keepBuilt)
+		}
+	}
+}
+
+// taggedMembers returns the members of the group under groupKey, starting with
+// those inherited by a scope
+func taggedMembers[T any](sl *ServiceLocator, groupKey taggedKey) []taggedMember[T] {
+	var inherited []taggedMember[T]
+	if sl.parent != nil {
+		inherited = taggedMembers[T](sl.parent, groupKey)
+	}
+
+	sl.mu.RLock()
+	members, _ := sl.groups[groupKey].(taggedGroup[T])
+	sl.mu.RUnlock()
+
+	return append(inherited, members...)
+}
@@ -0,0 +1,105 @@
+package locator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type StartupHook interface {
+	Start() string
+}
+
+type namedHook string
+
+func (h namedHook) Start() string { return string(h) }
+
+// Test GetByTag returns instances and provided services in registration order
+func TestGetByTag(t *testing.T) {
+	sl := locator.New()
+
+	var built int
+	locator.RegisterTagged[StartupHook](sl, namedHook("migrate"), "startup-hooks")
+	locator.RegisterTagged[StartupHook](sl, func() StartupHook {
+		built++
+		return namedHook("warm cache")
+	}, "startup-hooks", "cache")
+	locator.RegisterTagged[StartupHook](sl, namedHook("flush"), "shutdown-hooks")
+
+	scope := sl.Scope()
+	locator.RegisterTagged[StartupHook](scope, namedHook("scoped"), "startup-hooks")
+
+	hooks, err := locator.GetByTag[StartupHook](scope, "startup-hooks")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var started []string
+	for _, hook := range hooks {
+		started = append(started, hook.Start())
+	}
+	if len(started) != 3 || started[0] != "migrate" || started[1] != "warm cache" || started[2] != "scoped" {
+		t.Fatalf("expected [migrate warm cache scoped], got %v", started)
+	}
+
+	cache, err := locator.GetByTag[StartupHook](sl, "cache")
+	if err != nil || len(cache) != 1 || built != 1 {
+		t.Fatalf("expected the provided hook to be built once, got %v, %v, %d", cache, err, built)
+	}
+	if none, err := locator.GetByTag[StartupHook](sl, "unknown"); err != nil || len(none) != 0 {
+		t.Fatalf("expected no services, got %v, %v", none, err)
+	}
+	if locator.Has[StartupHook](sl) {
+		t.Fatalf("expected tagged services not to be registered under their type")
+	}
+}
+
+// Test GetByTag reports provider failures and RegisterTagged rejects other values
+func TestGetByTagErrors(t *testing.T) {
+	sl := locator.New()
+
+	errFailed := errors.New("failed")
+	locator.RegisterTagged[StartupHook](sl, func() (StartupHook, error) { return nil, errFailed }, "startup-hooks")
+	if _, err := locator.GetByTag[StartupHook](sl, "startup-hooks"); !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for a value that is not a StartupHook")
+		}
+	}()
+	locator.RegisterTagged[StartupHook](sl, 42, "startup-hooks")
+}
+
+// Test tagged services built by a locator are rebuilt by its clones and after
+// ResetInstances, once for all their tags
+func TestTaggedReset(t *testing.T) {
+	sl := locator.New()
+	var built int
+	locator.RegisterTagged[StartupHook](sl, func() StartupHook {
+		built++
+		return namedHook("warm cache")
+	}, "startup-hooks", "cache")
+	locator.GetByTag[StartupHook](sl, "startup-hooks")
+
+	clone := sl.Clone()
+	locator.GetByTag[StartupHook](clone, "startup-hooks")
+	locator.GetByTag[StartupHook](clone, "cache")
+	if built != 2 {
+		t.Fatalf("expected the clone to build its own service once, got %d builds", built)
+	}
+
+	shared := sl.Clone(locator.ShareSingletons())
+	locator.GetByTag[StartupHook](shared, "cache")
+	if built != 2 {
+		t.Fatalf("expected ShareSingletons to share the built service, got %d builds", built)
+	}
+
+	sl.ResetInstances()
+	locator.GetByTag[StartupHook](sl, "startup-hooks")
+	locator.GetByTag[StartupHook](sl, "cache")
+	if built != 3 {
+		t.Fatalf("expected ResetInstances to rebuild the service once, got %d builds", built)
+	}
+}
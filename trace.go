@@ -0,0 +1,90 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracer writes the resolution trees of a locator configured with WithTracing
+type tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// traceNode records one resolution and the resolutions its provider made
+type traceNode struct {
+	typeKey  any
+	kind     Kind
+	built    bool
+	site     string
+	elapsed  time.Duration
+	err      error
+	children []*traceNode
+}
+
+// resolve resolves typeKey as a node of the trace of sl, writing the trace when it
+// is the outermost resolution
+func (t *tracer) resolve(ctx context.Context, sl *ServiceLocator, typeKey any) (instance any, found bool, err error) {
+	node := &traceNode{typeKey: typeKey}
+	if sl.trace != nil {
+		t.mu.Lock()
+		sl.trace.children = append(sl.trace.children, node)
+		t.mu.Unlock()
+	}
+
+	start := time.Now()
+	view := &ServiceLocator{registry: sl.registry, frame: sl.frame, trace: node}
+	instance, found, err = view.resolveRegistration(ctx, typeKey)
+	node.elapsed = time.Since(start)
+	node.site = sl.callSite(typeKey)
+	node.err = err
+	if !found {
+		node.err = sl.missingError(typeKey)
+	}
+
+	if sl.trace == nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		var b strings.Builder
+		node.write(&b, 0)
+		t.w.Write([]byte(b.String()))
+	}
+	return instance, found, err
+}
+
+// write formats the node and its children, indented by depth
+func (n *traceNode) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%v %v", strings.Repeat("  ", depth), n.typeKey, n.kind)
+	if n.site != "" {
+		fmt.Fprintf(b, " (%s)", n.site)
+	}
+	switch {
+	case n.err != nil:
+		fmt.Fprintf(b, " failed after %v: %v\n", n.elapsed, n.err)
+	case n.built:
+		fmt.Fprintf(b, " built in %v\n", n.elapsed)
+	default:
+		b.WriteString(" cached\n")
+	}
+	for _, child := range n.children {
+		child.write(b, depth+1)
+	}
+}
+
+// callSite returns where typeKey was registered, looking through the ancestors of
+// a scope
+func (sl *ServiceLocator) callSite(typeKey any) string {
+	for p := sl; p != nil; p = p.parent {
+		p.mu.RLock()
+		site, exists := p.sites[typeKey]
+		p.mu.RUnlock()
+		if exists {
+			return site
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package locator_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test WithTracing writes the resolution tree once the outermost resolution ends
+func TestWithTracing(t *testing.T) {
+	var out strings.Builder
+	sl := locator.New(locator.WithTracing(&out))
+
+	locator.RegisterSingleton(sl, &ServiceB{})
+	locator.RegisterLazySingletonWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceC {
+		locator.Get[*ServiceD](sl)
+		return &ServiceC{}
+	})
+	locator.RegisterFactoryWithLocator(sl, func(sl *locator.ServiceLocator) *ServiceA {
+		locator.Get[*ServiceB](sl)
+		locator.Get[*ServiceC](sl)
+		return &ServiceA{}
+	})
+
+	if _, err := locator.Get[*ServiceA](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := regexp.MustCompile(`^` +
+		`\*locator_test\.ServiceA factory \(.*trace_test\.go:\d+\) built in \S+\n` +
+		`  \*locator_test\.ServiceB singleton \(.*trace_test\.go:\d+\) cached\n` +
+		`  \*locator_test\.ServiceC lazy \(.*trace_test\.go:\d+\) built in \S+\n` +
+		`    \*locator_test\.ServiceD unregistered failed after \S+: no provider registered for type \*locator_test\.ServiceD\n` +
+		`$`)
+	if !expected.MatchString(out.String()) {
+		t.Fatalf("expected trace matching %v, got\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,121 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validate checks that every type is registered and reports all missing ones in a
+// single joined error. Each element of types is either a value of the type to
+// check, such as (*Service)(nil), or a reflect.Type, which is needed for
+// interfaces. Nothing is constructed, so lazy singletons stay unbuilt. Types the
+// locator can resolve through WithAutoStructConstruction or
+// WithPointerValueFallback count as registered
+func Validate(sl *ServiceLocator, types ...any) error {
+	var errs []error
+	for _, t := range types {
+		typ, ok := t.(reflect.Type)
+		if !ok {
+			typ = reflect.TypeOf(t)
+		}
+		if sl.hasRegistration(typ) {
+			continue
+		}
+		if _, ok := sl.fallbackResolver(typ); ok {
+			continue
+		}
+		errs = append(errs, sl.missingError(typ))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateOption configures ServiceLocator.Validate
+type ValidateOption func(*validateOptions)
+
+// validateOptions holds the configuration of ServiceLocator.Validate
+type validateOptions struct {
+	construct bool
+}
+
+// ConstructAll makes Validate also resolve every registration once the declared
+// dependencies check out, so failing providers are reported as well. Singletons
+// built this way stay built, and factories run once. Scoped types are skipped
+// since they can only be resolved from a scope
+func ConstructAll() ValidateOption {
+	return func(o *validateOptions) {
+		o.construct = true
+	}
+}
+
+// Validate checks that every dependency declared with DependsOn by a registered
+// type can be resolved, and returns a single joined error listing each missing
+// one. Nothing is constructed unless ConstructAll is given. The Validate function
+// checks a given list of types instead
+func (sl *ServiceLocator) Validate(opts ...ValidateOption) error {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	sl.mu.RLock()
+	var dependents []any
+	for typeKey := range sl.dependencies {
+		if sl.isRegistered(typeKey) {
+			dependents = append(dependents, typeKey)
+		}
+	}
+	// Scoped types can only be resolved from a scope, and a built lazy singleton has
+	// both a provider and an instance but is resolved once
+	var registered []any
+	for typeKey, provider := range sl.providers {
+		if r, ok := provider.(resolver); !ok || r.kind() != KindScoped {
+			registered = append(registered, typeKey)
+		}
+	}
+	for typeKey := range sl.instances {
+		if _, exists := sl.providers[typeKey]; !exists {
+			registered = append(registered, typeKey)
+		}
+	}
+	declared := make(map[any][]reflect.Type, len(dependents))
+	for _, typeKey := range dependents {
+		declared[typeKey] = sl.dependencies[typeKey]
+	}
+	sl.mu.RUnlock()
+
+	// The checks take the lock themselves, so they run once it is released
+	sortByName(dependents)
+	var errs []error
+	for _, typeKey := range dependents {
+		for _, dep := range declared[typeKey] {
+			if sl.hasInherited(dep) {
+				continue
+			}
+			if _, ok := sl.fallbackResolver(dep); ok {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%v depends on unregistered type %v", typeKey, dep))
+		}
+	}
+	if len(errs) > 0 || !o.construct {
+		return errors.Join(errs...)
+	}
+
+	sortByName(registered)
+	for _, typeKey := range registered {
+		if _, _, err := sl.resolve(context.Background(), typeKey); err != nil {
+			errs = append(errs, fmt.Errorf("validate %v: %w", typeKey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sortByName sorts type keys by their printed name
+func sortByName(typeKeys []any) {
+	sort.Slice(typeKeys, func(i, j int) bool {
+		return fmt.Sprint(typeKeys[i]) < fmt.Sprint(typeKeys[j])
+	})
+}
@@ -0,0 +1,122 @@
+package locator_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Validate reports every missing type without constructing anything
+func TestValidate(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{}
+	})
+	locator.RegisterSingleton(sl, 42)
+
+	if err := locator.Validate(sl, (*TestService)(nil), 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if callCount != 0 {
+		t.Fatalf("expected no construction, got %d provider calls", callCount)
+	}
+
+	err := locator.Validate(sl, (*TestService)(nil), (*AnotherTestService)(nil), "", reflect.TypeOf((*Greeter)(nil)).Elem())
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	for _, missing := range []string{"*locator_test.AnotherTestService", "string", "locator_test.Greeter"} {
+		if !strings.Contains(err.Error(), "no provider registered for type "+missing) {
+			t.Fatalf("expected %s to be reported missing, got %v", missing, err)
+		}
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 3 {
+		t.Fatalf("expected 3 missing types, got %d: %v", len(lines), err)
+	}
+}
+
+// Test the Validate method reports every missing declared dependency
+func TestValidateMethod(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *ServiceA {
+		callCount++
+		return &ServiceA{}
+	})
+	locator.RegisterLazySingleton(sl, func() *ServiceC { return &ServiceC{} })
+	locator.DependsOn[*ServiceA](sl, typeB, typeC)
+	locator.DependsOn[*ServiceC](sl, typeD)
+
+	err := sl.Validate()
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expected := typeA.String() + " depends on unregistered type " + typeB.String() + "\n" +
+		typeC.String() + " depends on unregistered type " + typeD.String()
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+	if callCount != 0 {
+		t.Fatalf("expected no construction, got %d provider calls", callCount)
+	}
+
+	locator.RegisterSingleton(sl, &ServiceB{})
+	locator.RegisterSingleton(sl, &ServiceD{})
+	if err := sl.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if callCount != 0 {
+		t.Fatalf("expected no construction, got %d provider calls", callCount)
+	}
+}
+
+// Test ConstructAll makes Validate report failing providers
+func TestValidateConstructAll(t *testing.T) {
+	sl := locator.New()
+
+	var callCount int
+	locator.RegisterLazySingleton(sl, func() *TestService {
+		callCount++
+		return &TestService{}
+	})
+	locator.RegisterLazySingletonE(sl, func() (*AnotherTestService, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	err := sl.Validate(locator.ConstructAll())
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	if callCount != 1 {
+		t.Fatalf("expected 1 provider call, got %d", callCount)
+	}
+}
+
+// Test ConstructAll skips scoped types and resolves built singletons once
+func TestValidateConstructAllScoped(t *testing.T) {
+	sl := locator.New()
+	locator.RegisterScoped(sl, func() *UnitOfWork { return &UnitOfWork{} })
+	var resolutions int
+	sl.OnResolve(func(typ reflect.Type, _ any, _ time.Duration, _ error) {
+		if typ == reflect.TypeOf(&TestService{}) {
+			resolutions++
+		}
+	})
+	locator.RegisterLazySingleton(sl, func() *TestService { return &TestService{} })
+	locator.MustGet[*TestService](sl)
+
+	if err := sl.Validate(locator.ConstructAll()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolutions != 2 {
+		t.Fatalf("expected 2 resolutions, got %d", resolutions)
+	}
+}
@@ -0,0 +1,325 @@
+package locator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RegisterVersioned registers instance as a specific semantic version of T.
+// Several versions can be registered side by side; GetVersioned picks the highest
+// one satisfying a constraint and Get returns the highest registered version.
+// Registering a version that is already present replaces it
+func RegisterVersioned[T any](sl *ServiceLocator, version string, instance T) error {
+	v, err := parseVersion(version)
+	if err != nil {
+		return err
+	}
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	typeKey := getTypeKey[T]()
+	if err := sl.frozenError(typeKey); err != nil {
+		return err
+	}
+
+	vp, ok := sl.providers[typeKey].(*versionedProvider[T])
+	// Adding another version is not an overwrite, replacing one or another kind of
+	// registration is
+	overwrite := !ok && sl.isRegistered(typeKey)
+	if ok {
+		for _, entry := range vp.entries {
+			overwrite = overwrite || entry.version == v
+		}
+	}
+	if err := sl.admit(typeKey, KindSingleton, overwrite); err != nil {
+		return err
+	}
+	delete(sl.instances, typeKey)
+
+	if !ok {
+		vp = &versionedProvider[T]{}
+	} else {
+		// Copy on write so resolutions in flight keep a consistent view
+		vp = &versionedProvider[T]{entries: append([]versionedEntry[T](nil), vp.entries...)}
+	}
+	vp.add(v, instance)
+	sl.providers[typeKey] = vp
+	sl.changed()
+	return nil
+}
+
+// GetVersioned retrieves the highest registered version of T that satisfies
+// constraint. Supported constraints are exact versions ("1.2.0"), comparisons
+// (">=1.0.0", "<2.0.0"), caret ("^1.0.0") and tilde ("~1.2.0") ranges and "*".
+// Space separated constraints must all be satisfied. It resolves like Get, so a
+// scope or child locator finds the registrations of its ancestors, but fails if
+// the nearest registration of T is not a versioned registration
+func GetVersioned[T any](sl *ServiceLocator, constraint string) (T, error) {
+	var zero T
+	matches, err := parseConstraint(constraint)
+	if err != nil {
+		return zero, err
+	}
+
+	typeKey := getTypeKey[T]()
+	if _, ok := sl.inheritedProvider(typeKey).(*versionedProvider[T]); !ok {
+		return zero, fmt.Errorf("no versioned provider registered for type %v", typeKey)
+	}
+
+	ctx := context.WithValue(context.Background(), versionConstraintKey{}, versionConstraint{
+		typeKey:    typeKey,
+		constraint: constraint,
+		matches:    matches,
+	})
+	instance, found, err := sl.resolve(ctx, typeKey)
+	if !found {
+		return zero, sl.missingError(typeKey)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return castInstance[T](instance), nil
+}
+
+// versionConstraintKey is the context key under which GetVersioned passes its
+// constraint to the versioned provider
+type versionConstraintKey struct{}
+
+// versionConstraint is a parsed constraint on the versions of typeKey
+type versionConstraint struct {
+	typeKey    any
+	constraint string
+	matches    func(semver) bool
+}
+
+// versionedProvider holds every registered version of a service
+type versionedProvider[T any] struct {
+	entries []versionedEntry[T]
+	// decorated caches the decorated instance of each version
+	decorated sync.Map
+}
+
+// versionedEntry is a single registered version of a service
+type versionedEntry[T any] struct {
+	version  semver
+	instance T
+}
+
+// add inserts or replaces the entry for v, keeping entries sorted by descending version
+func (vp *versionedProvider[T]) add(v semver, instance T) {
+	for i := range vp.entries {
+		if vp.entries[i].version == v {
+			vp.entries[i].instance = instance
+			return
+		}
+	}
+	vp.entries = append(vp.entries, versionedEntry[T]{version: v, instance: instance})
+	sort.Slice(vp.entries, func(i, j int) bool {
+		return vp.entries[i].version.compare(vp.entries[j].version) > 0
+	})
+}
+
+// resolve returns the decorated highest registered version, or the highest one
+// satisfying the constraint GetVersioned passed in ctx
+func (vp *versionedProvider[T]) resolve(ctx context.Context, sl *ServiceLocator) (any, bool, error) {
+	entry, err := vp.latest()
+	if c, ok := ctx.Value(versionConstraintKey{}).(versionConstraint); ok && c.typeKey == getTypeKey[T]() {
+		entry, err = vp.highest(c)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if instance, ok := vp.decorated.Load(entry.version); ok {
+		return instance, false, nil
+	}
+	decorated, _ := vp.decorated.LoadOrStore(entry.version, decorate(sl, entry.instance))
+	return decorated, false, nil
+}
+
+// withoutDecorated returns a copy of vp with an empty cache
+func (vp *versionedProvider[T]) withoutDecorated() any {
+	return &versionedProvider[T]{entries: vp.entries}
+}
+
+// kind reports that vp holds prebuilt singletons
+func (vp *versionedProvider[T]) kind() Kind {
+	return KindSingleton
+}
+
+// latest returns the highest registered version
+func (vp *versionedProvider[T]) latest() (versionedEntry[T], error) {
+	if len(vp.entries) == 0 {
+		return versionedEntry[T]{}, fmt.Errorf("no provider registered for type %v", getTypeKey[T]())
+	}
+	return vp.entries[0], nil
+}
+
+// highest returns the highest registered version satisfying c
+func (vp *versionedProvider[T]) highest(c versionConstraint) (versionedEntry[T], error) {
+	// Entries are sorted by descending version, so the first match is the highest
+	for _, entry := range vp.entries {
+		if c.matches(entry.version) {
+			return entry, nil
+		}
+	}
+	return versionedEntry[T]{}, fmt.Errorf("no version of type %v satisfies %q", getTypeKey[T](), c.constraint)
+}
+
+// semver is a parsed major.minor.patch version
+type semver struct {
+	major, minor, patch int
+}
+
+// compare returns -1, 0 or 1 depending on whether v is lower, equal or higher than o
+func (v semver) compare(o semver) int {
+	switch {
+	case v.major != o.major:
+		return compareInt(v.major, o.major)
+	case v.minor != o.minor:
+		return compareInt(v.minor, o.minor)
+	default:
+		return compareInt(v.patch, o.patch)
+	}
+}
+
+// compareInt returns -1, 0 or 1 depending on whether a is lower, equal or higher than b
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parseVersion parses a full version such as "1.2.3" or "v1.2.3"
+func parseVersion(s string) (semver, error) {
+	v, parts, err := parsePartialVersion(s)
+	if err != nil {
+		return semver{}, err
+	}
+	if parts != 3 {
+		return semver{}, fmt.Errorf("invalid version %q", s)
+	}
+	return v, nil
+}
+
+// parsePartialVersion parses a version that may omit its minor and patch numbers,
+// returning how many components were present
+func parsePartialVersion(s string) (semver, int, error) {
+	fields := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(fields) > 3 {
+		return semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+
+	var numbers [3]int
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		numbers[i] = n
+	}
+	return semver{major: numbers[0], minor: numbers[1], patch: numbers[2]}, len(fields), nil
+}
+
+// parseConstraint turns a constraint string into a predicate over versions
+func parseConstraint(constraint string) (func(semver) bool, error) {
+	fields := strings.Fields(constraint)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid version constraint %q", constraint)
+	}
+
+	var checks []func(semver) bool
+	for _, field := range fields {
+		check, err := parseComparator(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+		checks = append(checks, check)
+	}
+
+	return func(v semver) bool {
+		for _, check := range checks {
+			if !check(v) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// parseComparator parses a single comparator such as ">=1.0.0" or "^1.2"
+func parseComparator(field string) (func(semver) bool, error) {
+	if field == "*" {
+		return func(semver) bool { return true }, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if !strings.HasPrefix(field, op) {
+			continue
+		}
+		base, parts, err := parsePartialVersion(strings.TrimPrefix(field, op))
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case ">=":
+			return func(v semver) bool { return v.compare(base) >= 0 }, nil
+		case "<=":
+			return func(v semver) bool { return v.compare(base) <= 0 }, nil
+		case ">":
+			return func(v semver) bool { return v.compare(base) > 0 }, nil
+		case "<":
+			return func(v semver) bool { return v.compare(base) < 0 }, nil
+		case "=":
+			return func(v semver) bool { return v == base }, nil
+		case "^":
+			return rangeCheck(base, caretUpper(base, parts)), nil
+		default:
+			return rangeCheck(base, tildeUpper(base, parts)), nil
+		}
+	}
+
+	base, _, err := parsePartialVersion(field)
+	if err != nil {
+		return nil, err
+	}
+	return func(v semver) bool { return v == base }, nil
+}
+
+// rangeCheck matches versions in the half-open range [lower, upper)
+func rangeCheck(lower, upper semver) func(semver) bool {
+	return func(v semver) bool {
+		return v.compare(lower) >= 0 && v.compare(upper) < 0
+	}
+}
+
+// caretUpper returns the exclusive upper bound of a caret range, which allows
+// changes that do not modify the left-most non-zero component
+func caretUpper(base semver, parts int) semver {
+	switch {
+	case base.major > 0 || parts == 1:
+		return semver{major: base.major + 1}
+	case base.minor > 0 || parts == 2:
+		return semver{minor: base.minor + 1}
+	default:
+		return semver{patch: base.patch + 1}
+	}
+}
+
+// tildeUpper returns the exclusive upper bound of a tilde range, which allows
+// patch level changes when a minor version is given and minor changes otherwise
+func tildeUpper(base semver, parts int) semver {
+	if parts == 1 {
+		return semver{major: base.major + 1}
+	}
+	return semver{major: base.major, minor: base.minor + 1}
+}
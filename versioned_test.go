@@ -0,0 +1,119 @@
+package locator_test
+
+import (
+	"testing"
+
+	"github.com/RobinHood3082/locator"
+)
+
+type Plugin struct {
+	Version string
+}
+
+// Test resolving the highest version matching a constraint
+func TestGetVersioned(t *testing.T) {
+	sl := locator.New()
+
+	for _, version := range []string{"1.0.0", "2.0.0", "v1.2.0", "1.1.5"} {
+		if err := locator.RegisterVersioned(sl, version, &Plugin{Version: version}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^1.0.0", "v1.2.0"},
+		{"~1.1.0", "1.1.5"},
+		{">=1.0.0 <1.2.0", "1.1.5"},
+		{"1.0.0", "1.0.0"},
+		{">1.2.0", "2.0.0"},
+		{"*", "2.0.0"},
+	}
+	for _, tt := range tests {
+		plugin, err := locator.GetVersioned[*Plugin](sl, tt.constraint)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.constraint, err)
+		}
+		if plugin.Version != tt.expected {
+			t.Fatalf("%s: expected %s, got %s", tt.constraint, tt.expected, plugin.Version)
+		}
+	}
+
+	// Get returns the highest registered version
+	plugin, err := locator.Get[*Plugin](sl)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if plugin.Version != "2.0.0" {
+		t.Fatalf("expected 2.0.0, got %s", plugin.Version)
+	}
+}
+
+// Test versioned resolution errors
+func TestGetVersionedErrors(t *testing.T) {
+	sl := locator.New()
+
+	if _, err := locator.GetVersioned[*Plugin](sl, "^1.0.0"); err == nil {
+		t.Fatalf("expected error for an unregistered type, got nil")
+	}
+
+	if err := locator.RegisterVersioned(sl, "1.x", &Plugin{}); err == nil {
+		t.Fatalf("expected error for an invalid version, got nil")
+	}
+
+	if err := locator.RegisterVersioned(sl, "0.2.3", &Plugin{Version: "0.2.3"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err := locator.GetVersioned[*Plugin](sl, "^0.3.0")
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectedError := `no version of type *locator_test.Plugin satisfies "^0.3.0"`
+	if err.Error() != expectedError {
+		t.Fatalf("expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+
+	if _, err := locator.GetVersioned[*Plugin](sl, ">=abc"); err == nil {
+		t.Fatalf("expected error for an invalid constraint, got nil")
+	}
+}
+
+// Test GetVersioned resolves like Get: through scopes, with the observer, and
+// decorating each version once
+func TestGetVersionedResolution(t *testing.T) {
+	var observed int
+	sl := locator.New(locator.WithObserver(func(locator.ResolveEvent) { observed++ }))
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		if err := locator.RegisterVersioned(sl, version, &Plugin{Version: version}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	var decorations int
+	locator.Decorate(sl, func(p *Plugin) *Plugin {
+		decorations++
+		return &Plugin{Version: p.Version + "+"}
+	})
+
+	scope := sl.Scope()
+	for i := 0; i < 2; i++ {
+		plugin, err := locator.GetVersioned[*Plugin](scope, "^1.0.0")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if plugin.Version != "1.0.0+" {
+			t.Fatalf("expected 1.0.0+, got %s", plugin.Version)
+		}
+	}
+	if decorations != 1 {
+		t.Fatalf("expected a single decoration, got %d", decorations)
+	}
+	if observed != 2 {
+		t.Fatalf("expected the observer to see 2 resolutions, got %d", observed)
+	}
+	if _, err := locator.GetVersioned[*Plugin](scope, "^3.0.0"); err == nil {
+		t.Fatalf("expected an unsatisfied constraint error, got nil")
+	}
+}
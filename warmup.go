@@ -0,0 +1,87 @@
+package locator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// WarmupResult describes the construction of one lazy singleton by Warmup
+type WarmupResult struct {
+	// Type is the name of the constructed type
+	Type string
+	// Duration is how long resolving the type took. It is close to zero for a
+	// singleton that was already built
+	Duration time.Duration
+	// Err is the construction error, if any
+	Err error
+}
+
+// Warmup constructs every lazy singleton up front, named and keyed ones included,
+// so the cost is paid at startup instead of on the first request. Singletons are
+// built in dependency order, so a service is only constructed after everything it
+// declared with DependsOn. Declared dependencies of other kinds, such as
+// factories, are left to the providers that need them. Nothing is constructed if
+// the declared dependencies form a cycle or reference an unregistered type. One
+// result is returned per singleton attempted, in construction order. Construction
+// errors are also returned joined together, and Warmup stops early if ctx is done
+func (sl *ServiceLocator) Warmup(ctx context.Context) ([]WarmupResult, error) {
+	sl.mu.RLock()
+	var lazy []reflect.Type
+	var keyed []any
+	for typeKey := range sl.providers {
+		if !sl.isLazy(typeKey) {
+			continue
+		}
+		if typ, ok := typeKey.(reflect.Type); ok {
+			lazy = append(lazy, typ)
+		} else {
+			keyed = append(keyed, typeKey)
+		}
+	}
+	// Sort the roots so that independent singletons are built in a stable order
+	sort.Slice(lazy, func(i, j int) bool {
+		return lazy[i].String() < lazy[j].String()
+	})
+	order, err := sl.dependencyOrder(lazy...)
+	typeKeys := make([]any, 0, len(order)+len(keyed))
+	for _, typ := range order {
+		if sl.isLazy(typ) {
+			typeKeys = append(typeKeys, typ)
+		}
+	}
+	// Keyed registrations cannot declare dependencies, so they are built last
+	sortByName(keyed)
+	typeKeys = append(typeKeys, keyed...)
+	sl.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]WarmupResult, 0, len(typeKeys))
+	var errs []error
+	for _, typeKey := range typeKeys {
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+		start := time.Now()
+		_, found, err := sl.resolve(ctx, typeKey)
+		result := WarmupResult{Type: fmt.Sprint(typeKey), Duration: time.Since(start)}
+		if found && err != nil {
+			result.Err = err
+			errs = append(errs, fmt.Errorf("warmup %v: %w", typeKey, err))
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// isLazy reports whether typeKey is registered as a lazy singleton. The caller
+// must hold sl.mu
+func (sl *ServiceLocator) isLazy(typeKey any) bool {
+	r, ok := sl.providers[typeKey].(resolver)
+	return ok && r.kind() == KindLazySingleton
+}
@@ -0,0 +1,158 @@
+package locator_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobinHood3082/locator"
+)
+
+// Test Warmup builds declared dependencies before their dependents
+func TestWarmupOrder(t *testing.T) {
+	sl := locator.New()
+
+	var built []string
+	locator.RegisterLazySingleton(sl, func() *ServiceA {
+		built = append(built, "A")
+		return &ServiceA{}
+	})
+	locator.RegisterLazySingleton(sl, func() *ServiceB {
+		built = append(built, "B")
+		return &ServiceB{}
+	})
+	locator.DependsOn[*ServiceA](sl, typeB)
+
+	if _, err := sl.Warmup(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"B", "A"}
+	if !reflect.DeepEqual(built, expected) {
+		t.Fatalf("expected build order %v, got %v", expected, built)
+	}
+
+	// Warmed singletons are cached, so a later Get does not rebuild them
+	if _, err := locator.Get[*ServiceA](sl); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("expected no further construction, got %v", built)
+	}
+}
+
+// Test Warmup follows the full dependency closure
+func TestWarmupTransitive(t *testing.T) {
+	var built []string
+	sl := locator.New(locator.WithObserver(func(ev locator.ResolveEvent) {
+		if !ev.CacheHit {
+			built = append(built, ev.Type)
+		}
+	}))
+	registerABCD(sl)
+
+	if _, err := sl.Warmup(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{
+		"*locator_test.ServiceB",
+		"*locator_test.ServiceD",
+		"*locator_test.ServiceC",
+		"*locator_test.ServiceA",
+	}
+	if !reflect.DeepEqual(built, expected) {
+		t.Fatalf("expected build order %v, got %v", expected, built)
+	}
+}
+
+// Test Warmup reports dependency cycles without constructing anything
+func TestWarmupCycle(t *testing.T) {
+	sl := locator.New()
+
+	var built int
+	locator.RegisterLazySingleton(sl, func() *ServiceA {
+		built++
+		return &ServiceA{}
+	})
+	locator.RegisterLazySingleton(sl, func() *ServiceB {
+		built++
+		return &ServiceB{}
+	})
+	locator.DependsOn[*ServiceA](sl, typeB)
+	locator.DependsOn[*ServiceB](sl, typeA)
+
+	_, err := sl.Warmup(context.Background())
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	expectCycle(t, err, "warmup_test.go", "*locator_test.ServiceA", "*locator_test.ServiceB", "*locator_test.ServiceA")
+	if built != 0 {
+		t.Fatalf("expected nothing to be constructed, got %d", built)
+	}
+}
+
+// Test Warmup reports the outcome and duration of each construction
+func TestWarmupResults(t *testing.T) {
+	sl := locator.New()
+
+	locator.RegisterLazySingleton(sl, func() *ServiceA {
+		time.Sleep(10 * time.Millisecond)
+		return &ServiceA{}
+	})
+	locator.RegisterLazySingletonE(sl, func() (*ServiceB, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	results, err := sl.Warmup(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected construction error, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %v", results)
+	}
+
+	if results[0].Type != typeA.String() || results[0].Err != nil {
+		t.Fatalf("expected successful %s, got %+v", typeA, results[0])
+	}
+	if results[0].Duration < 10*time.Millisecond {
+		t.Fatalf("expected duration of at least 10ms, got %v", results[0].Duration)
+	}
+	if results[1].Type != typeB.String() || results[1].Err == nil {
+		t.Fatalf("expected failed %s, got %+v", typeB, results[1])
+	}
+}
+
+// Test Warmup builds named lazy singletons but no factories or singletons of
+// other kinds, even when they are declared dependencies
+func TestWarmupKinds(t *testing.T) {
+	sl := locator.New()
+
+	var namedBuilt bool
+	locator.RegisterLazySingletonNamed(sl, "primary", func() *TestService {
+		namedBuilt = true
+		return &TestService{}
+	})
+	var factoryRuns int
+	locator.RegisterFactory(sl, func() *ServiceB {
+		factoryRuns++
+		return &ServiceB{}
+	})
+	locator.RegisterLazySingleton(sl, func() *ServiceA { return &ServiceA{} })
+	locator.DependsOn[*ServiceA](sl, typeB)
+
+	results, err := sl.Warmup(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !namedBuilt {
+		t.Fatalf("expected the named singleton to be built")
+	}
+	if factoryRuns != 0 {
+		t.Fatalf("expected the factory not to run, got %d runs", factoryRuns)
+	}
+	if len(results) != 2 || results[0].Type != typeA.String() || !strings.Contains(results[1].Type, "primary") {
+		t.Fatalf("expected results for %s and the named singleton, got %+v", typeA, results)
+	}
+}